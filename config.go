@@ -18,10 +18,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -41,10 +43,95 @@ var (
 	ErrorIncorrectPath                  = errors.New("Incorrect path")
 	ErrorUnknownConfigType              = errors.New("Unknown config type")
 	ErrorIncorrectValueType             = errors.New("Incorrect value type")
+	ErrorValueOverflow                  = errors.New("Value overflows type")
 	ErrorUnsupportedTypeToLoadValue     = errors.New("Unsupported field type")
 	ErrorIncorrectValueToLoadFromConfig = errors.New("Inccorect value to load from config")
+	ErrorRequiredValueNotFound          = errors.New("Required value not found")
+	ErrorArrayLengthMismatch            = errors.New("Array length mismatch")
+	ErrorIncorrectValueFormat           = errors.New("Incorrect value format")
+	ErrorAmbiguousKey                   = errors.New("Ambiguous key")
 )
 
+// PathError describes error of access to value by specified path. It wraps one of errors
+// listed above or error of custom loader, so it may be checked using errors.Is.
+type PathError struct {
+	// Path to value in config.
+	Path string
+	// Name of expected Go type (empty if it is unknown).
+	Type string
+	// Raw value found in config (empty if value is absent or is not scalar).
+	Value string
+	// Path to config file (empty if config is not read from file).
+	File string
+	// Line and column of value in config source (0 if they are unknown).
+	Line   int
+	Column int
+	// Underlying cause of error.
+	Err error
+}
+
+// Error returns description of error.
+func (e *PathError) Error() string {
+	message := fmt.Sprintf("%s by path '%s'", e.Err, e.Path)
+	if len(e.Value) != 0 {
+		message += fmt.Sprintf(", value '%s'", e.Value)
+	}
+	if len(e.Type) != 0 {
+		message += fmt.Sprintf(", expected type %s", e.Type)
+	}
+	position := Position{File: e.File, Line: e.Line, Column: e.Column}
+	if location := position.String(); len(location) != 0 {
+		message = fmt.Sprintf("%s: %s", location, message)
+	}
+	return message
+}
+
+// Unwrap returns underlying cause of error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// LoadErrors contains all errors occurred while loading value with 'CollectAllErrors' setting.
+type LoadErrors struct {
+	Errors []error
+}
+
+// Error returns description of all errors.
+func (e *LoadErrors) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("Cannot load %d value(s):\n\t%s", len(e.Errors), strings.Join(messages, "\n\t"))
+}
+
+// Unwrap returns all errors, so errors.Is and errors.As check each of them.
+func (e *LoadErrors) Unwrap() []error {
+	return e.Errors
+}
+
+// Is checks whether any of errors matches target. It is used by errors.Is of Go versions that
+// do not support 'Unwrap() []error'.
+func (e *LoadErrors) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of errors that matches target. It is used by errors.As of Go versions
+// that do not support 'Unwrap() []error'.
+func (e *LoadErrors) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValueSliceCreator type of function that creates slice for value grabber.
 type ValueSliceCreator func(length int)
 
@@ -56,7 +143,8 @@ type StringValueGrabber func(string) error
 
 // Config represents configuration with convenient access methods.
 type Config interface {
-	// GrabValue may be used to retrieve single value of complex type.
+	// GrabValue may be used to retrieve single value of complex type. Numbers of JSON configs
+	// are passed to grabber as 'json.Number' to keep their exact values.
 	GrabValue(path string, grabber ValueGrabber) (err error)
 	// GrabValues may be used to retrieve list of values of complex type. Argument 'delim' may
 	// be used into method to split list into separate elements.
@@ -70,6 +158,8 @@ type Config interface {
 	GetFloat(path string) (value float64, err error)
 	// GetInt returns int value by specified path.
 	GetInt(path string) (value int64, err error)
+	// GetUint returns unsigned int value by specified path.
+	GetUint(path string) (value uint64, err error)
 
 	// GetStrings returns list of string by specified path. Argument 'delim' may be used
 	// to split list into separate elements.
@@ -83,9 +173,19 @@ type Config interface {
 	// GetInts returns list of int by specified path. Argument 'delim' may be used
 	// to split list into separate elements.
 	GetInts(path string, delim string) (value []int64, err error)
+	// GetUints returns list of unsigned int by specified path. Argument 'delim' may be used
+	// to split list into separate elements.
+	GetUints(path string, delim string) (value []uint64, err error)
+	// GetIntRanges returns list of int by specified path, ranges like '8000-8010' are
+	// expanded into all numbers of range. Argument 'delim' may be used to split list into
+	// separate elements.
+	GetIntRanges(path string, delim string) (value []int64, err error)
 
 	// GetConfigPart returns as 'Config' config part by specified path.
 	GetConfigPart(path string) (config Config, err error)
+
+	// Position returns position of value by specified path in config source.
+	Position(path string) (position Position, err error)
 }
 
 // *** Functions to create config object. ***
@@ -104,7 +204,20 @@ func ReadTypedConfig(configPath string, configType string) (Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ReadConfigFromReader(configFile, configType)
+	defer configFile.Close()
+
+	config, err := ReadConfigFromReader(configFile, configType)
+	if err != nil {
+		var parseError *ParseError
+		if errors.As(err, &parseError) {
+			parseError.Position.File = configPath
+		}
+		return nil, err
+	}
+	if fileConfig, ok := config.(sourceFileConfig); ok {
+		fileConfig.setSourceFile(configPath)
+	}
+	return config, nil
 }
 
 // ReadConfigFromReader reads and parses config of specified type from reader.
@@ -136,9 +249,9 @@ func CreateConfig(configData []byte, configType string) (Config, error) {
 func GrabStringValue(c Config, path string, grabber StringValueGrabber) (err error) {
 	value, err := c.GetString(path)
 	if err != nil {
-		return err
+		return setPathErrorType(err, "")
 	}
-	return grabber(value)
+	return wrapPathError(c, path, value, grabber(value))
 }
 
 // GrabStringValues retrieves values from config using specified grabber and slice creator.
@@ -147,50 +260,96 @@ func GrabStringValues(c Config, path string, delim string,
 
 	values, err := c.GetStrings(path, delim)
 	if err != nil {
-		return err
+		return setPathErrorType(err, "")
 	}
 	creator(len(values))
 	for _, value := range values {
 		if err = grabber(value); err != nil {
-			return err
+			return wrapPathError(c, path, value, err)
 		}
 	}
 	return nil
 }
 
-// GetDuration returns duration value from config.
+// GetDuration returns duration value from config. Units 'd' and 'w' are supported in addition
+// to units of 'time.ParseDuration' (see 'ParseDuration').
 func GetDuration(c Config, path string) (value time.Duration, err error) {
-	return value, GrabStringValue(c, path, func(data string) error {
-		value, err = time.ParseDuration(data)
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = ParseDuration(data)
+		return err
+	}), "time.Duration")
+}
+
+// GetByteSize returns byte size value from config (see 'ByteSize').
+func GetByteSize(c Config, path string) (value ByteSize, err error) {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = ParseByteSize(data)
 		return err
-	})
+	}), "config.ByteSize")
 }
 
-// GetTime returns time value from config.
+// GetPercent returns ratio value from config (see 'Percent').
+func GetPercent(c Config, path string) (value Percent, err error) {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = ParsePercent(data)
+		return err
+	}), "config.Percent")
+}
+
+// GetTime returns time value in RFC3339 format from config. Time in other formats may be
+// loaded by 'TunedLoadValue' with 'LoadSettings.Time'.
 func GetTime(c Config, path string) (value time.Time, err error) {
 	return GetTimeFormat(c, path, time.RFC3339)
 }
 
 // GetTimeFormat returns time value from config using custom format to parsing.
 func GetTimeFormat(c Config, path string, format string) (value time.Time, err error) {
-	return value, GrabStringValue(c, path, func(data string) error {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
 		value, err = time.Parse(format, data)
 		return err
-	})
+	}), "time.Time")
 }
 
 // GetDurations returns duration values from config. Argument 'delim' may be used
 // to split array into separate elements.
 func GetDurations(c Config, path string, delim string) (value []time.Duration, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]time.Duration, 0, cap) },
 		func(data string) error {
 			var parsed time.Duration
-			if parsed, err = time.ParseDuration(data); err == nil {
+			if parsed, err = ParseDuration(data); err == nil {
+				value = append(value, parsed)
+			}
+			return err
+		}), "[]time.Duration")
+}
+
+// GetByteSizes returns byte size values from config. Argument 'delim' may be used
+// to split array into separate elements.
+func GetByteSizes(c Config, path string, delim string) (value []ByteSize, err error) {
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
+		func(cap int) { value = make([]ByteSize, 0, cap) },
+		func(data string) error {
+			var parsed ByteSize
+			if parsed, err = ParseByteSize(data); err == nil {
+				value = append(value, parsed)
+			}
+			return err
+		}), "[]config.ByteSize")
+}
+
+// GetPercents returns ratio values from config. Argument 'delim' may be used
+// to split array into separate elements.
+func GetPercents(c Config, path string, delim string) (value []Percent, err error) {
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
+		func(cap int) { value = make([]Percent, 0, cap) },
+		func(data string) error {
+			var parsed Percent
+			if parsed, err = ParsePercent(data); err == nil {
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]config.Percent")
 }
 
 // GetTimes returns time values from config. Argument 'delim' may be used
@@ -202,7 +361,7 @@ func GetTimes(c Config, path string, delim string) (value []time.Time, err error
 // GetTimesFormat returns time value from config using custom format to parsing. Argument
 // 'delim' may be used to split array into separate elements.
 func GetTimesFormat(c Config, path string, format string, delim string) (value []time.Time, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]time.Time, 0, cap) },
 		func(data string) error {
 			var parsed time.Time
@@ -210,7 +369,39 @@ func GetTimesFormat(c Config, path string, format string, delim string) (value [
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]time.Time")
+}
+
+// *** Generic functions to read values. ***
+
+// Get returns value of type T by specified path. Value is loaded in the same way as by
+// 'LoadValue', so T may be any type supported by it: numeric types (with overflow checks),
+// strings, bools, durations, times, slices of them and types with custom loaders.
+func Get[T any](c Config, path string) (value T, err error) {
+	if err = LoadValue(c, path, &value); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// GetOr returns value of type T by specified path or default value if value is absent or
+// cannot be loaded. Use 'Get' to distinguish incorrect values from absent ones.
+func GetOr[T any](c Config, path string, defaultValue T) T {
+	value, err := Get[T](c, path)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// MustGet returns value of type T by specified path and panics if value cannot be loaded.
+func MustGet[T any](c Config, path string) T {
+	value, err := Get[T](c, path)
+	if err != nil {
+		panic(err)
+	}
+	return value
 }
 
 // *** Function to load value of arbitrary type. ***
@@ -225,19 +416,54 @@ type Loadable interface {
 // StringValueLoader type of function that loads string value from config.
 type StringValueLoader func(string) (reflect.Value, error)
 
+// ConfigLoadable is interface that contains method to load value from config part.
+//
+// LoadFromConfig receives config part by path of value, so it may load values of any shape
+// (scalar value of config part may be got by path '/').
+type ConfigLoadable interface {
+	LoadFromConfig(c Config) (err error)
+}
+
+// ConfigValueLoader type of function that loads value from config part.
+type ConfigValueLoader func(Config) (reflect.Value, error)
+
 // LoadSettings is settings that used to load values from config.
 type LoadSettings struct {
-	// Delimiter that will be used to split array into separate elements.
+	// Delimiter that will be used to split array into separate elements (and map into pairs).
 	Delim string
+	// Delimiter that will be used to split pair of map into key and value.
+	KeyValueDelim string
 	// Flag that specifies whether to ignore missing field errors.
 	IgnoreMissingFieldErrors bool
+	// Flag that specifies whether to continue loading of structure fields after error. All
+	// errors are returned as 'LoadErrors'.
+	CollectAllErrors bool
+	// Flag that disables validation of loaded value (see 'Validator').
+	SkipValidation bool
 	// Custom loaders.
 	Loaders map[string]StringValueLoader
+	// Custom loaders that receive config part. They have priority over 'Loaders'.
+	ConfigLoaders map[string]ConfigValueLoader
+	// Words that are parsed as bool values (in lower case). If it is not specified, vocabulary
+	// of 'GetDefaultBoolVocabulary' is used.
+	BoolVocabulary map[string]bool
+	// Flag that specifies whether to load bools and numbers from string values of JSON and YAML
+	// configs (like values of XML and INI configs).
+	Lenient bool
+	// Settings of splitting string values into elements of slices. If it is not specified,
+	// values are split by list getters of config.
+	List *ListSettings
+	// Settings of loading 'time.Time' values. If it is specified, they are used instead of
+	// loader of 'time.Time' from 'Loaders'.
+	Time *TimeSettings
+	// Flag that specifies whether to match keys of config and names of structure fields
+	// after normalization (see 'NormalizeKeys').
+	NormalizeKeys bool
 }
 
 // GetDefaultLoadSettings returns settings that may be used to load value from config.
 func GetDefaultLoadSettings(ignoreMissingFieldErrors bool) LoadSettings {
-	return LoadSettings{Delim: defaultArrayDelimiter,
+	return LoadSettings{Delim: defaultArrayDelimiter, KeyValueDelim: defaultKeyValueDelimiter,
 		IgnoreMissingFieldErrors: ignoreMissingFieldErrors,
 		Loaders:                  defaultLoaders}
 }
@@ -252,7 +478,28 @@ var (
 			return reflect.ValueOf(nil), err
 		},
 		"time.Duration": func(data string) (reflect.Value, error) {
-			value, err := time.ParseDuration(data)
+			value, err := ParseDuration(data)
+			if err == nil {
+				return reflect.ValueOf(value), nil
+			}
+			return reflect.ValueOf(nil), err
+		},
+		"time.Weekday": func(data string) (reflect.Value, error) {
+			value, err := ParseWeekday(data)
+			if err == nil {
+				return reflect.ValueOf(value), nil
+			}
+			return reflect.ValueOf(nil), err
+		},
+		"time.Location": func(data string) (reflect.Value, error) {
+			value, err := time.LoadLocation(data)
+			if err == nil {
+				return reflect.ValueOf(value).Elem(), nil
+			}
+			return reflect.ValueOf(nil), err
+		},
+		"*time.Location": func(data string) (reflect.Value, error) {
+			value, err := time.LoadLocation(data)
 			if err == nil {
 				return reflect.ValueOf(value), nil
 			}
@@ -264,7 +511,12 @@ var (
 // Function can load simple types (bool, int, uint, float, string), arrays of simple types and
 // structure. Structure can be loaded field by field, in this case for path construction in
 // first place used tag 'config', in second--name of field. Also structure can be loaded using
-// custom loader (of type 'StringValueLoader') or 'Loadable' interface.
+// custom loader (of type 'StringValueLoader' or 'ConfigValueLoader') or 'Loadable' and
+// 'ConfigLoadable' interfaces. Name in tag 'config' may be followed by options 'required' and
+// 'omitempty' (field may be absent or empty, it is left unchanged in this case), field with
+// tag 'config:"-"' is skipped. Tag 'default' specifies value that is used if field is absent
+// (or empty with 'omitempty'). Maps are loaded from string values like 'env=prod team=core',
+// slices of integers from string values may contain ranges like '8000-8010'.
 func LoadValue(c Config, path string, value interface{}) (err error) {
 	return parametrizedLoadValue(c, false, path, value)
 }
@@ -289,11 +541,18 @@ func parametrizedLoadValue(c Config, ignoreMissingFieldErrors bool, path string,
 // must be pointer. Function can load simple types (bool, int, uint, float, string), arrays of
 // simple types and structure. Structure can  be loaded field by field, in this case for path
 // construction in first place used tag 'config', in second--name of field. Also structure can be
-// loaded using custom loader (of type 'StringValueLoader') or 'Loadable' interface.
+// loaded using custom loader (of type 'StringValueLoader') or 'Loadable' interface. Tag
+// options are described in 'LoadValue'. Loaded value is validated as described in 'Validator'.
 func TunedLoadValue(c Config, settings LoadSettings, path string, value interface{}) (err error) {
 	val := reflect.ValueOf(value)
 	if val.Kind() != reflect.Ptr || !val.Elem().CanAddr() || !val.Elem().CanSet() {
 		return ErrorIncorrectValueToLoadFromConfig
 	}
-	return loadValue(c, settings, path, val.Elem())
+	if settings.NormalizeKeys {
+		c = NormalizeKeys(c)
+	}
+	if err = loadValue(c, settings, path, val.Elem()); err != nil || settings.SkipValidation {
+		return err
+	}
+	return validateValue(c, settings, path, val.Elem())
 }
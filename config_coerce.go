@@ -0,0 +1,125 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+var (
+	// defaultBoolVocabulary is used to parse bool values of all configs.
+	defaultBoolVocabulary = map[string]bool{
+		"true": true, "false": false, "t": true, "f": false, "1": true, "0": false,
+		"yes": true, "no": false, "y": true, "n": false, "on": true, "off": false,
+		"enabled": true, "disabled": false}
+)
+
+// GetDefaultBoolVocabulary returns words that are parsed as bool values by default: true/false,
+// t/f, 1/0, yes/no, y/n, on/off and enabled/disabled.
+func GetDefaultBoolVocabulary() map[string]bool {
+	vocabulary := make(map[string]bool, len(defaultBoolVocabulary))
+	for word, value := range defaultBoolVocabulary {
+		vocabulary[word] = value
+	}
+	return vocabulary
+}
+
+// ParseBool parses bool value using vocabulary of lower case words. Case of value is ignored.
+func ParseBool(data string, vocabulary map[string]bool) (bool, error) {
+	value, exist := vocabulary[strings.ToLower(strings.TrimSpace(data))]
+	if !exist {
+		return false, ErrorIncorrectValueType
+	}
+	return value, nil
+}
+
+// typedValueConfig is implemented by configs that contain values of different types (like
+// JSON and YAML), so string values are distinguished from numbers and bools.
+type typedValueConfig interface {
+	typedValue(path string) (interface{}, error)
+}
+
+// isCoercible checks whether values of kind are coerced from strings in lenient mode.
+func isCoercible(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// coerceValue loads bool or number from string value of typed config in lenient mode. String
+// is parsed in the same way as values of XML and INI configs.
+func coerceValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, bool, error) {
+	typedConfig, ok := c.(typedValueConfig)
+	if !settings.Lenient || !ok || !isCoercible(value.Kind()) {
+		return reflect.ValueOf(nil), false, nil
+	}
+	data, err := typedConfig.typedValue(path)
+	if stringData, isString := data.(string); isString && err == nil {
+		result := reflect.New(value.Type()).Elem()
+		err = loadStringValue(settings, path, stringData, result)
+		return result, true, wrapPathError(c, path, stringData, err)
+	}
+	return reflect.ValueOf(nil), false, nil
+}
+
+// coerceSliceValue loads slice of bools or numbers from list or string value of typed config in
+// lenient mode. Elements of list are loaded separately, so list may contain both strings and
+// numbers.
+func coerceSliceValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, bool, error) {
+	typedConfig, ok := c.(typedValueConfig)
+	if !settings.Lenient || !ok || !isCoercible(value.Type().Elem().Kind()) {
+		return reflect.ValueOf(nil), false, nil
+	}
+	data, err := typedConfig.typedValue(path)
+	if stringData, isString := data.(string); isString && err == nil {
+		result, err := loadListValue(c, settings, path, stringData, value)
+		return result, true, err
+	}
+	elements, isList := data.([]interface{})
+	if !isList || err != nil {
+		return reflect.ValueOf(nil), false, nil
+	}
+	result := reflect.MakeSlice(value.Type(), len(elements), len(elements))
+	for i, element := range elements {
+		elementConfig := &jsonConfig{data: map[string]interface{}{stringValueName: element}}
+		err = loadValue(elementConfig, settings, joinPath(stringValueName), result.Index(i))
+		if pathError, ok := err.(*PathError); ok {
+			return reflect.ValueOf(nil), true, wrapPathError(c, path, pathError.Value, pathError.Err)
+		} else if err != nil {
+			return reflect.ValueOf(nil), true, err
+		}
+	}
+	return result, true, nil
+}
+
+// loadBoolValue loads bool value using vocabulary from settings. Values of typed configs are
+// loaded by vocabulary only in lenient mode.
+func loadBoolValue(c Config, settings LoadSettings, path string) (reflect.Value, error) {
+	if _, typed := c.(typedValueConfig); typed || settings.BoolVocabulary == nil {
+		value, err := c.GetBool(path)
+		return reflect.ValueOf(value), err
+	}
+	var value bool
+	err := GrabStringValue(c, path, func(data string) (err error) {
+		value, err = ParseBool(data, settings.BoolVocabulary)
+		return err
+	})
+	return reflect.ValueOf(value), err
+}
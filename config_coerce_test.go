@@ -0,0 +1,128 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type coercedData struct {
+	Enabled bool      `config:"enabled"`
+	Debug   bool      `config:"debug"`
+	Port    uint16    `config:"port"`
+	Ratio   float64   `config:"ratio"`
+	Retries int       `config:"retries"`
+	Flags   []bool    `config:"flags"`
+	Limits  []int32   `config:"limits"`
+	Weights []float32 `config:"weights"`
+}
+
+// Tests.
+func TestParseBool(t *testing.T) {
+	for data, expected := range map[string]bool{
+		"true": true, "FALSE": false, "Yes": true, "no": false, "ON": true, "off": false,
+		"Enabled": true, "disabled": false, "y": true, "N": false, "1": true, " 0 ": false,
+	} {
+		value, err := ParseBool(data, defaultBoolVocabulary)
+		require.NoError(t, err, data)
+		require.Equal(t, expected, value, data)
+	}
+
+	_, err := ParseBool("maybe", defaultBoolVocabulary)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+
+	vocabulary := GetDefaultBoolVocabulary()
+	vocabulary["da"] = true
+	require.NotContains(t, defaultBoolVocabulary, "da", "Default vocabulary must not be changed")
+}
+
+func TestBoolVocabularyInAllFormats(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"enabled": true, "flags": [false, true]}`,
+		YAML: "enabled: on\nflags: [no, yes]\n",
+		XML:  "<enabled>Enabled</enabled><flags>off Y</flags>",
+		INI:  "enabled = yes\nflags = disabled on\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		enabled, err := config.GetBool("/enabled")
+		require.NoError(t, err, configType)
+		require.True(t, enabled, configType)
+
+		flags, err := config.GetBools("/flags", defaultArrayDelimiter)
+		require.NoError(t, err, configType)
+		require.Equal(t, []bool{false, true}, flags, configType)
+	}
+}
+
+func TestCustomBoolVocabulary(t *testing.T) {
+	config, err := CreateConfigFromString("[server]\nenabled = da\ndebug = yes\nflags = da net\n", INI)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.BoolVocabulary = map[string]bool{"da": true, "net": false}
+
+	var flags []bool
+	require.NoError(t, TunedLoadValue(config, settings, "/server/flags", &flags))
+	require.Equal(t, []bool{true, false}, flags)
+
+	var enabled bool
+	require.NoError(t, TunedLoadValue(config, settings, "/server/enabled", &enabled))
+	require.True(t, enabled)
+
+	err = TunedLoadValue(config, settings, "/server/debug", &enabled)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+}
+
+func TestLenientLoading(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"enabled": "yes", "debug": false, "port": "8080", "ratio": "0.5", "retries": 3,
+			"flags": ["on", false], "limits": ["10", 1000000], "weights": "0.5 1.5"}`,
+		YAML: "enabled: 'yes'\ndebug: false\nport: '8080'\nratio: '0.5'\nretries: 3\n" +
+			"flags: ['on', false]\nlimits: ['10', 1000000]\nweights: 0.5 1.5\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value coercedData
+		err = LoadValue(config, "/", &value)
+		require.ErrorIs(t, err, ErrorIncorrectValueType, "Strings must not be coerced by default")
+
+		settings := GetDefaultLoadSettings(false)
+		settings.Lenient = true
+		require.NoError(t, TunedLoadValue(config, settings, "/", &value), configType)
+		require.Equal(t, coercedData{Enabled: true, Port: 8080, Ratio: 0.5, Retries: 3,
+			Flags: []bool{true, false}, Limits: []int32{10, 1000000}, Weights: []float32{0.5, 1.5}},
+			value, configType)
+	}
+
+	config, err := CreateConfigFromString(`{"port": "http", "limits": ["10", "70000000000"]}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.Lenient = true
+	var port uint16
+	err = TunedLoadValue(config, settings, "/port", &port)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+	require.EqualError(t, err, "1:2: Incorrect value type by path '/port', value 'http', "+
+		"expected type uint16")
+
+	var limits []int32
+	err = TunedLoadValue(config, settings, "/limits", &limits)
+	require.ErrorIs(t, err, ErrorValueOverflow)
+}
@@ -0,0 +1,200 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ChangeType describes kind of change of config value.
+type ChangeType int
+
+// Constants for available change types.
+const (
+	ChangeAdded ChangeType = iota
+	ChangeRemoved
+	ChangeModified
+)
+
+// String returns name of change type.
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// Change describes difference of single value between two versions of config.
+type Change struct {
+	// Path to changed value.
+	Path string
+	// Kind of change.
+	Type ChangeType
+	// Value from old config (nil if value was added).
+	OldValue interface{}
+	// Value from new config (nil if value was removed).
+	NewValue interface{}
+}
+
+// ChangeHandler type of function that is called when config part is changed. Arguments are
+// config parts by subscribed path, any of them may be nil if config part is absent.
+type ChangeHandler func(old, new Config)
+
+// valueWalker is implemented by configs that can enumerate their leaf values.
+type valueWalker interface {
+	walkValues(visit valueVisitor)
+}
+
+// valueVisitor type of function that receives path and value of leaf of config tree.
+type valueVisitor func(path string, value interface{})
+
+// Diff returns list of changes between two versions of config sorted by path. Leaf values
+// are compared using inner representation of config, so configs must have the same type.
+// Nil config is treated as empty one.
+func Diff(old, new Config) []Change {
+	oldValues := collectValues(old)
+	newValues := collectValues(new)
+
+	changes := make([]Change, 0)
+	for path, oldValue := range oldValues {
+		newValue, exist := newValues[path]
+		if !exist {
+			changes = append(changes, Change{Path: path, Type: ChangeRemoved, OldValue: oldValue})
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Path: path, Type: ChangeModified,
+				OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for path, newValue := range newValues {
+		if _, exist := oldValues[path]; !exist {
+			changes = append(changes, Change{Path: path, Type: ChangeAdded, NewValue: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// ChangeNotifier calls subscribed handlers when config parts under their paths are changed.
+type ChangeNotifier struct {
+	mutex         sync.Mutex
+	subscriptions []subscription
+}
+
+type subscription struct {
+	path    string
+	handler ChangeHandler
+}
+
+// NewChangeNotifier creates notifier without subscriptions.
+func NewChangeNotifier() *ChangeNotifier {
+	return &ChangeNotifier{}
+}
+
+// OnChange subscribes handler on changes of values by specified path or under it.
+func (n *ChangeNotifier) OnChange(path string, handler ChangeHandler) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.subscriptions = append(n.subscriptions, subscription{path: joinPath(splitPath(path)...),
+		handler: handler})
+}
+
+// Notify compares two versions of config and calls handlers which paths contain changes.
+// Handlers are called in order of subscription. Returns list of found changes.
+func (n *ChangeNotifier) Notify(old, new Config) []Change {
+	changes := Diff(old, new)
+	if len(changes) == 0 {
+		return changes
+	}
+
+	n.mutex.Lock()
+	subscriptions := append([]subscription(nil), n.subscriptions...)
+	n.mutex.Unlock()
+
+	for _, s := range subscriptions {
+		if hasChangesUnder(changes, s.path) {
+			s.handler(getConfigPartOrNil(old, s.path), getConfigPartOrNil(new, s.path))
+		}
+	}
+	return changes
+}
+
+// Diff helpers.
+func collectValues(c Config) map[string]interface{} {
+	values := make(map[string]interface{})
+	if walker, ok := c.(valueWalker); ok {
+		walker.walkValues(func(path string, value interface{}) {
+			values[path] = value
+		})
+	}
+	return values
+}
+
+func hasChangesUnder(changes []Change, path string) bool {
+	for _, change := range changes {
+		if isSubPath(change.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSubPath(path string, prefix string) bool {
+	if prefix == pathDelimiter || path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, prefix+pathDelimiter)
+}
+
+func getConfigPartOrNil(c Config, path string) Config {
+	if c == nil {
+		return nil
+	}
+	part, err := c.GetConfigPart(path)
+	if err != nil {
+		return nil
+	}
+	return part
+}
+
+// walkTree walks over tree of maps produced by json and yaml parsers.
+func walkTree(data interface{}, path string, visit valueVisitor) {
+	switch node := data.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			walkTree(child, joinPath(path, key), visit)
+		}
+	case map[interface{}]interface{}:
+		for key, child := range node {
+			walkTree(child, joinPath(path, toPathPart(key)), visit)
+		}
+	default:
+		visit(joinPath(path), normalizeJSONNumbers(data))
+	}
+}
+
+func toPathPart(key interface{}) string {
+	if part, err := parseJSONString(key); err == nil {
+		return part
+	}
+	return ""
+}
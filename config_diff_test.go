@@ -0,0 +1,175 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createConfigsToDiff(t *testing.T, configType, oldData, newData string) (Config, Config) {
+	oldConfig, err := CreateConfigFromString(oldData, configType)
+	require.NoError(t, err, "Cannot parse old config")
+
+	newConfig, err := CreateConfigFromString(newData, configType)
+	require.NoError(t, err, "Cannot parse new config")
+
+	return oldConfig, newConfig
+}
+
+// Tests.
+func TestDiffJson(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, JSON,
+		`{"database": {"host": "db1", "port": 5432}, "cache": {"size": 10}, "debug": true}`,
+		`{"database": {"host": "db2", "port": 5432}, "cache": {}, "log": [1, 2]}`)
+
+	changes := Diff(oldConfig, newConfig)
+	require.Equal(t, []Change{
+		{Path: "/cache/size", Type: ChangeRemoved, OldValue: int64(10)},
+		{Path: "/database/host", Type: ChangeModified, OldValue: "db1", NewValue: "db2"},
+		{Path: "/debug", Type: ChangeRemoved, OldValue: true},
+		{Path: "/log", Type: ChangeAdded, NewValue: []interface{}{int64(1), int64(2)}},
+	}, changes)
+}
+
+func TestDiffYaml(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, YAML,
+		"database:\n  host: db1\n  port: 5432\n",
+		"database:\n  host: db1\n  port: 5433\n  user: root\n")
+
+	changes := Diff(oldConfig, newConfig)
+	require.Equal(t, []Change{
+		{Path: "/database/port", Type: ChangeModified, OldValue: 5432, NewValue: 5433},
+		{Path: "/database/user", Type: ChangeAdded, NewValue: "root"},
+	}, changes)
+}
+
+func TestDiffXml(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, XML,
+		`<xml><database port="5432"><host>db1</host></database><cache/></xml>`,
+		`<xml><database port="5433"><host>db1</host></database><cache size="10"/></xml>`)
+
+	changes := Diff(oldConfig, newConfig)
+	require.Equal(t, []Change{
+		{Path: "/xml/cache/@size", Type: ChangeAdded, NewValue: "10"},
+		{Path: "/xml/database/@port", Type: ChangeModified, OldValue: "5432", NewValue: "5433"},
+	}, changes)
+}
+
+func TestDiffXmlRepeatedElements(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, XML,
+		`<a><i>1</i><i>2</i><j>1</j></a>`, `<a><i>1</i><i>3</i><j>1</j></a>`)
+
+	changes := Diff(oldConfig, newConfig)
+	require.Equal(t, []Change{
+		{Path: "/a/i", Type: ChangeModified, OldValue: []interface{}{"1", "2"},
+			NewValue: []interface{}{"1", "3"}},
+	}, changes)
+}
+
+func TestDiffIni(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, INI,
+		"[database]\nhost=db1\nport=5432\n[cache]\nsize=10",
+		"[database]\nhost=db1\nport=5432\n[log]\nlevel=debug")
+
+	changes := Diff(oldConfig, newConfig)
+	require.Equal(t, []Change{
+		{Path: "/cache/size", Type: ChangeRemoved, OldValue: "10"},
+		{Path: "/log/level", Type: ChangeAdded, NewValue: "debug"},
+	}, changes)
+}
+
+func TestDiffIniWithoutSections(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, INI, "host=db1\nport=5432", "host=db2\nport=5432")
+
+	changes := Diff(oldConfig, newConfig)
+	require.Equal(t, []Change{
+		{Path: "/host", Type: ChangeModified, OldValue: "db1", NewValue: "db2"},
+	}, changes)
+}
+
+func TestDiffConfigParts(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, JSON, twoLevelJSONConfig, oneLevelJSONConfig)
+
+	oldPart, err := oldConfig.GetConfigPart("/first")
+	require.NoError(t, err, "Cannot get config part")
+
+	require.Empty(t, Diff(oldPart, newConfig))
+}
+
+func TestDiffNilConfig(t *testing.T) {
+	config, err := CreateConfigFromString(`{"element": "value"}`, JSON)
+	require.NoError(t, err, "Cannot parse config")
+
+	require.Equal(t, []Change{{Path: "/element", Type: ChangeAdded, NewValue: "value"}},
+		Diff(nil, config))
+	require.Equal(t, []Change{{Path: "/element", Type: ChangeRemoved, OldValue: "value"}},
+		Diff(config, nil))
+}
+
+func TestChangeNotifier(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, YAML,
+		"database:\n  host: db1\ncache:\n  size: 10\n",
+		"database:\n  host: db2\ncache:\n  size: 10\n")
+
+	notifier := NewChangeNotifier()
+
+	var databaseCalls int
+	notifier.OnChange("/database", func(old, new Config) {
+		databaseCalls++
+
+		oldHost, err := old.GetString("/host")
+		require.NoError(t, err, "Cannot get old value")
+		require.Equal(t, "db1", oldHost)
+
+		newHost, err := new.GetString("/host")
+		require.NoError(t, err, "Cannot get new value")
+		require.Equal(t, "db2", newHost)
+	})
+	var cacheCalls int
+	notifier.OnChange("/cache", func(old, new Config) { cacheCalls++ })
+	var rootCalls int
+	notifier.OnChange("/", func(old, new Config) { rootCalls++ })
+	var similarPrefixCalls int
+	notifier.OnChange("/data", func(old, new Config) { similarPrefixCalls++ })
+
+	changes := notifier.Notify(oldConfig, newConfig)
+	require.Len(t, changes, 1)
+	require.Equal(t, 1, databaseCalls)
+	require.Equal(t, 0, cacheCalls)
+	require.Equal(t, 1, rootCalls)
+	require.Equal(t, 0, similarPrefixCalls)
+
+	notifier.Notify(newConfig, newConfig)
+	require.Equal(t, 1, databaseCalls)
+	require.Equal(t, 1, rootCalls)
+}
+
+func TestChangeNotifierAbsentPart(t *testing.T) {
+	oldConfig, newConfig := createConfigsToDiff(t, JSON, `{}`, `{"database": {"host": "db1"}}`)
+
+	notifier := NewChangeNotifier()
+
+	called := false
+	notifier.OnChange("database", func(old, new Config) {
+		called = true
+		require.Nil(t, old)
+		require.NotNil(t, new)
+	})
+
+	notifier.Notify(oldConfig, newConfig)
+	require.True(t, called, "Handler must be called")
+}
@@ -15,8 +15,12 @@
 package config
 
 import (
+	"encoding"
+	"errors"
+	"fmt"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -27,6 +31,10 @@ const (
 	defaultArrayDelimiter = " "
 	// TagKey tag name of structure fields.
 	tagKey = "config"
+	// DefaultTagKey tag name of default values of structure fields.
+	defaultTagKey = "default"
+	// StringValueName name of value in config that is created to load value from string.
+	stringValueName = "value"
 )
 
 // Heplers.
@@ -77,52 +85,249 @@ func getConfigType(configPath string) string {
 	return extension
 }
 
+// Error helpers.
+
+// sourceFileConfig is implemented by configs that remember file they are read from.
+type sourceFileConfig interface {
+	sourceFile() string
+	setSourceFile(file string)
+}
+
+// wrapPathError converts error to 'PathError' and fills its absent fields.
+func wrapPathError(c Config, path string, value string, err error) error {
+	if err == nil {
+		return nil
+	}
+	result := &PathError{Err: err}
+	if pathError, ok := err.(*PathError); ok {
+		copied := *pathError
+		result = &copied
+	}
+	if len(result.Path) == 0 {
+		result.Path = joinPath(path)
+	}
+	if len(result.Value) == 0 {
+		result.Value = value
+	}
+	if fileConfig, ok := c.(sourceFileConfig); ok && len(result.File) == 0 {
+		result.File = fileConfig.sourceFile()
+	}
+	if result.Line == 0 {
+		if position, err := c.Position(result.Path); err == nil {
+			result.Line, result.Column = position.Line, position.Column
+		}
+	}
+	return result
+}
+
+// setPathErrorType sets expected type of value to 'PathError', other errors are
+// returned as is.
+func setPathErrorType(err error, valueType string) error {
+	if pathError, ok := err.(*PathError); ok {
+		copied := *pathError
+		copied.Type = valueType
+		return &copied
+	}
+	return err
+}
+
+func newLoadError(c Config, path string, valueType reflect.Type, err error) error {
+	return setPathErrorType(wrapPathError(c, path, "", err), valueType.String())
+}
+
 // Load value implementations.
 func loadValue(c Config, settings LoadSettings, path string, value reflect.Value) (err error) {
 	var loadedValue reflect.Value
 	if loadedValue, err = loadSingleValue(c, settings, path, value); err == nil {
 		value.Set(loadedValue)
-	} else if err == ErrorNotFound && settings.IgnoreMissingFieldErrors {
+	} else if settings.CollectAllErrors && value.Kind() == reflect.Ptr && loadedValue.IsValid() {
+		// Partially loaded value is kept as values of structures loaded in place.
+		value.Set(loadedValue)
+	} else if errors.Is(err, ErrorNotFound) && settings.IgnoreMissingFieldErrors {
 		return nil
 	}
 	return err
 }
 
 func loadSingleValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, error) {
-	if loader := getCustomLoader(c, settings, value.Type()); loader != nil {
-		if data, err := c.GetString(path); err == nil {
-			return loader(data, value)
-		} else {
+	if configLoader := getConfigLoader(settings, value.Type()); configLoader != nil {
+		result, err := loadConfigPart(c, path, value, configLoader)
+		return result, newValueLoadError(c, path, value.Type(), err)
+	}
+	if value.Kind() == reflect.Ptr && !hasOwnLoader(settings, value.Type()) {
+		return loadPointerValue(c, settings, path, value)
+	}
+	if rawLoader, ok := c.(rawValueLoader); ok && value.CanAddr() && !hasOwnLoader(settings, value.Type()) {
+		if loaded, err := rawLoader.loadRawValue(path, value); loaded {
+			return value, newLoadError(c, path, value.Type(), err)
+		}
+	}
+	loader := getCustomLoader(c, settings, value.Type())
+	if loader == nil && value.Kind() == reflect.Struct {
+		return loadStructValueByFields(c, settings, path, value)
+	}
+	result, err := loadLeafValue(c, settings, path, value, loader)
+	return result, newValueLoadError(c, path, value.Type(), err)
+}
+
+func newValueLoadError(c Config, path string, valueType reflect.Type, err error) error {
+	if pathError, ok := err.(*PathError); ok && pathError.Path != concatPaths(path) {
+		// Error of nested value already contains its type.
+		return err
+	}
+	return newLoadError(c, path, valueType, err)
+}
+
+func loadLeafValue(c Config, settings LoadSettings, path string, value reflect.Value,
+	loader valueLoader) (reflect.Value, error) {
+
+	if loader != nil {
+		data, err := c.GetString(path)
+		if err != nil {
 			return reflect.ValueOf(nil), err
 		}
+		result, err := loader(data, value)
+		return result, wrapPathError(c, path, data, err)
+	}
+	if result, coerced, err := coerceValue(c, settings, path, value); coerced {
+		return result, err
 	}
 	switch value.Kind() {
 	case reflect.Bool:
-		value, err := c.GetBool(path)
-		return reflect.ValueOf(value), err
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return loadBoolValue(c, settings, path)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		resultValue, err := c.GetInt(path)
-		return reflect.ValueOf(resultValue).Convert(value.Type()), err
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		return convertInt(path, resultValue, value.Type())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		resultValue, err := c.GetUint(path)
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		return convertUint(path, resultValue, value.Type())
 	case reflect.Float32, reflect.Float64:
 		resultValue, err := c.GetFloat(path)
-		return reflect.ValueOf(resultValue).Convert(value.Type()), err
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		return convertFloat(path, resultValue, value.Type())
 	case reflect.String:
 		value, err := c.GetString(path)
 		return reflect.ValueOf(value), err
 	case reflect.Slice:
 		return loadSliceValue(c, settings, path, value)
-	case reflect.Struct:
-		return loadStructValueByFields(c, settings, path, value)
+	case reflect.Array:
+		return loadArrayValue(c, settings, path, value)
+	case reflect.Map:
+		return loadMapValue(c, settings, path, value)
+	case reflect.Interface:
+		if value.NumMethod() == 0 {
+			return loadValueTree(c, path, value.Type())
+		}
 	}
 	return reflect.ValueOf(nil), ErrorUnsupportedTypeToLoadValue
 }
 
+// loadPointerValue loads value pointed by pointer. Pointer is allocated if it is nil.
+func loadPointerValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, error) {
+	pointer := value
+	if pointer.IsNil() {
+		pointer = reflect.New(value.Type().Elem())
+	}
+	loadedValue, err := loadSingleValue(c, settings, path, pointer.Elem())
+	if err != nil && (!settings.CollectAllErrors || !loadedValue.IsValid()) {
+		return reflect.ValueOf(nil), err
+	}
+	pointer.Elem().Set(loadedValue)
+	return pointer, err
+}
+
+func loadArrayValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, error) {
+	slice := reflect.New(reflect.SliceOf(value.Type().Elem())).Elem()
+	values, err := loadSliceValue(c, settings, path, slice)
+	if err != nil {
+		return reflect.ValueOf(nil), err
+	}
+	if values.Len() != value.Len() {
+		return reflect.ValueOf(nil), fmt.Errorf("%w: found %d element(s)", ErrorArrayLengthMismatch,
+			values.Len())
+	}
+	result := reflect.New(value.Type()).Elem()
+	reflect.Copy(result, values)
+	return result, nil
+}
+
+// valueTreeConfig is implemented by configs that can return their content as tree of
+// maps ('map[string]interface{}'), lists ('[]interface{}') and scalar values.
+type valueTreeConfig interface {
+	valueTree() interface{}
+}
+
+// loadValueTree loads generic tree of values to empty interface. Values that cannot be
+// got as config part (like XML attributes) are loaded as strings.
+func loadValueTree(c Config, path string, valueType reflect.Type) (reflect.Value, error) {
+	result := reflect.New(valueType).Elem()
+	configPart, err := c.GetConfigPart(path)
+	if treeConfig, ok := configPart.(valueTreeConfig); ok && err == nil {
+		if tree := treeConfig.valueTree(); tree != nil {
+			result.Set(reflect.ValueOf(tree))
+		}
+		return result, nil
+	}
+	data, err := c.GetString(path)
+	if err != nil {
+		return reflect.ValueOf(nil), err
+	}
+	result.Set(reflect.ValueOf(data))
+	return result, nil
+}
+
+// newValueTree copies tree of values parsed from JSON or YAML, keys of maps are converted
+// to strings, numbers of JSON are converted to integers or floats by normalizeJSONNumbers.
+func newValueTree(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		tree := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			tree[key] = newValueTree(child)
+		}
+		return tree
+	case map[interface{}]interface{}:
+		tree := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			tree[toPathPart(key)] = newValueTree(child)
+		}
+		return tree
+	case []interface{}:
+		tree := make([]interface{}, len(value))
+		for i, child := range value {
+			tree[i] = newValueTree(child)
+		}
+		return tree
+	}
+	return normalizeJSONNumbers(data)
+}
+
 func loadSliceValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, error) {
+	if result, coerced, err := coerceSliceValue(c, settings, path, value); coerced {
+		return result, err
+	}
+	if listConfig, ok := c.(listValueConfig); ok {
+		if elements, isList := listConfig.listValue(path); isList {
+			return loadListElements(c, settings, path, elements, value)
+		}
+	}
 	elementType := value.Type().Elem()
+	if settings.List != nil || isSplitByLoader(elementType) {
+		if data, err := c.GetString(path); err == nil {
+			return loadListValue(c, settings, path, data, value)
+		}
+	}
 	if loader := getCustomLoader(c, settings, elementType); loader != nil {
 		if values, err := c.GetStrings(path, settings.Delim); err == nil {
-			return loadSlice(values, settings, value, loader)
+			return loadSlice(c, path, values, value, loader)
 		} else {
 			return reflect.ValueOf(nil), err
 		}
@@ -132,14 +337,23 @@ func loadSliceValue(c Config, settings LoadSettings, path string, value reflect.
 		value, err := c.GetBools(path, settings.Delim)
 		return reflect.ValueOf(value), err
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		value, err := c.GetInts(path, settings.Delim)
-		return copySlice(err, reflect.ValueOf(value), elementType), err
+		values, err := c.GetInts(path, settings.Delim)
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		return copySlice(path, values, value.Type(), convertInt)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		value, err := c.GetInts(path, settings.Delim)
-		return copySlice(err, reflect.ValueOf(value), elementType), err
+		values, err := c.GetUints(path, settings.Delim)
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		return copySlice(path, values, value.Type(), convertUint)
 	case reflect.Float32:
-		value, err := c.GetFloats(path, settings.Delim)
-		return copySlice(err, reflect.ValueOf(value), elementType), err
+		values, err := c.GetFloats(path, settings.Delim)
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		return copySlice(path, values, value.Type(), convertFloat)
 	case reflect.Float64:
 		value, err := c.GetFloats(path, settings.Delim)
 		return reflect.ValueOf(value), err
@@ -153,18 +367,224 @@ func loadSliceValue(c Config, settings LoadSettings, path string, value reflect.
 func loadStructValueByFields(c Config, settings LoadSettings, path string,
 	value reflect.Value) (result reflect.Value, err error) {
 
-	for i := 0; i < value.NumField() && (err == nil || (err == ErrorNotFound && settings.IgnoreMissingFieldErrors)); i++ {
-		fieldValue := value.Field(i)
-		fieldPath := joinPath(path, getFieldName(value, i))
-		err = loadValue(c, settings, fieldPath, fieldValue)
+	if settings.CollectAllErrors {
+		return loadAllStructFields(c, settings, path, value)
+	}
+	for i := 0; i < value.NumField() && err == nil; i++ {
+		err = loadStructField(c, settings, path, value, i)
 	}
 	return value, err
 }
 
+func loadAllStructFields(c Config, settings LoadSettings, path string,
+	value reflect.Value) (reflect.Value, error) {
+
+	var errs []error
+	for i := 0; i < value.NumField(); i++ {
+		if err := loadStructField(c, settings, path, value, i); err != nil {
+			if loadErrors, ok := err.(*LoadErrors); ok {
+				errs = append(errs, loadErrors.Errors...)
+			} else {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return value, &LoadErrors{Errors: errs}
+	}
+	return value, nil
+}
+
+func loadStructField(c Config, settings LoadSettings, path string, value reflect.Value, i int) error {
+	options := getFieldOptions(value.Type().Field(i))
+	fieldValue := value.Field(i)
+	if options.embedded && isLoadedByFields(c, settings, indirectType(fieldValue.Type())) {
+		return loadEmbeddedStruct(c, settings, path, fieldValue)
+	}
+	if options.skip || !fieldValue.CanSet() {
+		return nil
+	}
+	fieldPath := joinPath(path, options.name)
+	exist, empty := checkValue(c, fieldPath)
+	if exist && fieldValue.Kind() == reflect.Ptr && isNullValue(c, fieldPath) {
+		// Null value of pointer is the same as absent one.
+		exist = false
+	}
+	if !exist || (empty && options.omitEmpty) {
+		switch {
+		case options.hasDefault:
+			return loadDefaultValue(settings, fieldPath, options.defaultValue, fieldValue)
+		case options.required:
+			return newLoadError(c, fieldPath, fieldValue.Type(), ErrorRequiredValueNotFound)
+		case options.omitEmpty || (!exist && fieldValue.Kind() == reflect.Ptr):
+			return nil
+		}
+	}
+	return loadValue(c, settings, fieldPath, fieldValue)
+}
+
+// loadEmbeddedStruct loads fields of embedded structure by path of parent structure.
+func loadEmbeddedStruct(c Config, settings LoadSettings, path string, value reflect.Value) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			if !value.CanSet() {
+				return nil
+			}
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	_, err := loadStructValueByFields(c, settings, path, value)
+	return err
+}
+
+// isLoadedByFields checks whether value of type is structure that is loaded field by field.
+func isLoadedByFields(c Config, settings LoadSettings, valueType reflect.Type) bool {
+	return valueType.Kind() == reflect.Struct && getCustomLoader(c, settings, valueType) == nil &&
+		getConfigLoader(settings, valueType) == nil
+}
+
+func indirectType(valueType reflect.Type) reflect.Type {
+	if valueType.Kind() == reflect.Ptr {
+		return valueType.Elem()
+	}
+	return valueType
+}
+
+// checkValue checks whether value by path exists in config and is empty string. Structured
+// values (like XML elements with children) are not empty even if their text is empty.
+func checkValue(c Config, path string) (exist bool, empty bool) {
+	data, err := c.GetString(path)
+	if err == nil {
+		return true, len(data) == 0 && !isStructuredValue(c, path)
+	}
+	if _, err = c.GetConfigPart(path); err == nil {
+		return true, false
+	}
+	return !errors.Is(err, ErrorNotFound), false
+}
+
+// isNullValue checks whether value by path is null (like 'null' of JSON or '~' of YAML).
+func isNullValue(c Config, path string) bool {
+	configPart, err := c.GetConfigPart(path)
+	treeConfig, ok := configPart.(valueTreeConfig)
+	return err == nil && ok && treeConfig.valueTree() == nil
+}
+
+// isStructuredValue checks whether value by path is map or list.
+func isStructuredValue(c Config, path string) bool {
+	configPart, err := c.GetConfigPart(path)
+	if treeConfig, ok := configPart.(valueTreeConfig); ok && err == nil {
+		switch treeConfig.valueTree().(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		}
+	}
+	return false
+}
+
+// loadDefaultValue loads default value from tag of field using the same loaders as for values
+// from config.
+func loadDefaultValue(settings LoadSettings, path string, defaultValue string, value reflect.Value) error {
+	return loadStringValue(settings, path, defaultValue, value)
+}
+
+// loadStringValue loads value from string using the same loaders as for values from config.
+// Paths of errors are replaced by specified path.
+func loadStringValue(settings LoadSettings, path string, data string, value reflect.Value) error {
+	element := newXMLElement()
+	element.Value = data
+	root := newXMLElement()
+	root.AddChild(stringValueName, element)
+
+	err := loadValue(&xmlConfig{data: root}, settings, joinPath(stringValueName), value)
+	if pathError, ok := err.(*PathError); ok {
+		return &PathError{Path: path, Type: pathError.Type, Value: data, Err: pathError.Err}
+	}
+	return err
+}
+
+type configPartLoader func(Config, reflect.Value) (reflect.Value, error)
+
+var (
+	configLoadableType = reflect.TypeOf((*ConfigLoadable)(nil)).Elem()
+)
+
+func getConfigLoader(settings LoadSettings, valueType reflect.Type) configPartLoader {
+	if loader, exist := settings.ConfigLoaders[valueType.String()]; exist {
+		return func(c Config, value reflect.Value) (reflect.Value, error) {
+			loadedValue, err := loader(c)
+			if err == nil {
+				value.Set(loadedValue)
+			}
+			return value, err
+		}
+	} else if reflect.PtrTo(valueType).Implements(configLoadableType) {
+		return func(c Config, value reflect.Value) (reflect.Value, error) {
+			loadableValue, _ := value.Addr().Interface().(ConfigLoadable)
+			err := loadableValue.LoadFromConfig(c)
+			return value, err
+		}
+	}
+	return nil
+}
+
+// loadConfigPart passes config part by path to loader. Paths in errors of loader are
+// converted to paths in config.
+func loadConfigPart(c Config, path string, value reflect.Value, loader configPartLoader) (reflect.Value, error) {
+	configPart, err := c.GetConfigPart(path)
+	if err != nil {
+		return reflect.ValueOf(nil), err
+	}
+	result, err := loader(configPart, value)
+	if pathError, ok := err.(*PathError); ok {
+		copied := *pathError
+		copied.Path = concatPaths(path, pathError.Path)
+		err = &copied
+	}
+	return result, wrapPathError(c, path, "", err)
+}
+
 type valueLoader func(string, reflect.Value) (reflect.Value, error)
 
+// rawValueLoader is implemented by configs that can pass raw subtree of config to
+// unmarshalers of their formats (like 'json.Unmarshaler').
+type rawValueLoader interface {
+	loadRawValue(path string, value reflect.Value) (loaded bool, err error)
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// useRawValue checks whether value should be loaded by unmarshaler of config format instead
+// of string loaders. Unmarshaler is used for structured nodes and for types that cannot be
+// loaded from string. Slices are unmarshaled as whole if their elements are unmarshalers.
+func useRawValue(valueType reflect.Type, unmarshalerType reflect.Type, data interface{},
+	isStructured func(interface{}) bool) bool {
+
+	structured := isStructured(data)
+	if elements, ok := data.([]interface{}); ok && valueType.Kind() == reflect.Slice {
+		valueType, structured = valueType.Elem(), false
+		for _, element := range elements {
+			structured = structured || isStructured(element)
+		}
+	}
+	pointerType := reflect.PtrTo(valueType)
+	return pointerType.Implements(unmarshalerType) &&
+		(structured || !pointerType.Implements(textUnmarshalerType))
+}
+
+// hasOwnLoader checks whether type has loader that has priority over unmarshalers.
+func hasOwnLoader(settings LoadSettings, valueType reflect.Type) bool {
+	_, exist := settings.Loaders[valueType.String()]
+	return exist || isLoadable(valueType) || getTimeLoader(settings, valueType) != nil
+}
+
 func getCustomLoader(c Config, settings LoadSettings, valueType reflect.Type) valueLoader {
-	if loader, exist := settings.Loaders[valueType.String()]; exist {
+	if loader := getTimeLoader(settings, valueType); loader != nil {
+		return loader
+	} else if loader, exist := settings.Loaders[valueType.String()]; exist {
 		return func(data string, value reflect.Value) (reflect.Value, error) {
 			loadedValue, err := loader(data)
 			if err == nil {
@@ -178,6 +598,12 @@ func getCustomLoader(c Config, settings LoadSettings, valueType reflect.Type) va
 			err := loadableValue.LoadValueFromConfig(data)
 			return value, err
 		}
+	} else if reflect.PtrTo(valueType).Implements(textUnmarshalerType) {
+		return func(data string, value reflect.Value) (reflect.Value, error) {
+			unmarshaler, _ := value.Addr().Interface().(encoding.TextUnmarshaler)
+			err := unmarshaler.UnmarshalText([]byte(data))
+			return value, err
+		}
 	}
 	return nil
 }
@@ -187,7 +613,7 @@ func isLoadable(valueType reflect.Type) bool {
 	return reflect.PtrTo(valueType).Implements(loadableType)
 }
 
-func loadSlice(values []string, settings LoadSettings, value reflect.Value,
+func loadSlice(c Config, path string, values []string, value reflect.Value,
 	loader valueLoader) (reflect.Value, error) {
 
 	var err error
@@ -195,29 +621,84 @@ func loadSlice(values []string, settings LoadSettings, value reflect.Value,
 	for i, data := range values {
 		_, err = loader(data, outputValues.Index(i))
 		if err != nil {
-			break
+			return outputValues, wrapPathError(c, path, data, err)
 		}
 	}
-	return outputValues, err
+	return outputValues, nil
 }
 
-func copySlice(err error, srcSlice reflect.Value, dstSliceElemType reflect.Type) reflect.Value {
-	var dstSlice reflect.Value
-	if err == nil {
-		dstSlice = reflect.MakeSlice(reflect.SliceOf(dstSliceElemType),
-			srcSlice.Len(), srcSlice.Len())
-		for i := 0; i < srcSlice.Len(); i += 1 {
-			dstSlice.Index(i).Set(srcSlice.Index(i).Convert(dstSliceElemType))
+func copySlice[T any](path string, srcSlice []T, dstSliceType reflect.Type,
+	convert func(string, T, reflect.Type) (reflect.Value, error)) (reflect.Value, error) {
+
+	dstSlice := reflect.MakeSlice(dstSliceType, len(srcSlice), len(srcSlice))
+	for i, srcValue := range srcSlice {
+		dstValue, err := convert(path, srcValue, dstSliceType.Elem())
+		if err != nil {
+			return reflect.ValueOf(nil), err
 		}
+		dstSlice.Index(i).Set(dstValue)
+	}
+	return dstSlice, nil
+}
+
+func convertInt(path string, value int64, valueType reflect.Type) (reflect.Value, error) {
+	result := reflect.New(valueType).Elem()
+	if result.OverflowInt(value) {
+		return reflect.ValueOf(nil), &PathError{Path: path, Value: strconv.FormatInt(value, 10),
+			Type: valueType.String(), Err: ErrorValueOverflow}
+	}
+	result.SetInt(value)
+	return result, nil
+}
+
+func convertUint(path string, value uint64, valueType reflect.Type) (reflect.Value, error) {
+	result := reflect.New(valueType).Elem()
+	if result.OverflowUint(value) {
+		return reflect.ValueOf(nil), &PathError{Path: path, Value: strconv.FormatUint(value, 10),
+			Type: valueType.String(), Err: ErrorValueOverflow}
 	}
-	return dstSlice
+	result.SetUint(value)
+	return result, nil
 }
 
-func getFieldName(value reflect.Value, i int) string {
-	fieldType := value.Type().Field(i)
-	fieldName := fieldType.Tag.Get(tagKey)
-	if len(fieldName) != 0 {
-		return fieldName
+func convertFloat(path string, value float64, valueType reflect.Type) (reflect.Value, error) {
+	result := reflect.New(valueType).Elem()
+	if result.OverflowFloat(value) {
+		return reflect.ValueOf(nil), &PathError{Path: path, Value: strconv.FormatFloat(value, 'g', -1, 64),
+			Type: valueType.String(), Err: ErrorValueOverflow}
+	}
+	result.SetFloat(value)
+	return result, nil
+}
+
+// fieldOptions contains options of structure field specified in its tags.
+type fieldOptions struct {
+	name         string
+	skip         bool
+	embedded     bool
+	required     bool
+	omitEmpty    bool
+	hasDefault   bool
+	defaultValue string
+}
+
+func getFieldOptions(field reflect.StructField) fieldOptions {
+	tag := field.Tag.Get(tagKey)
+	tagParts := strings.Split(tag, ",")
+	options := fieldOptions{name: tagParts[0], skip: tag == "-" || !field.IsExported()}
+	if len(options.name) == 0 {
+		options.name = field.Name
+		// Embedded structures without name in tag are flattened into parent structure.
+		options.embedded = field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct
+	}
+	for _, option := range tagParts[1:] {
+		switch option {
+		case "required":
+			options.required = true
+		case "omitempty":
+			options.omitEmpty = true
+		}
 	}
-	return fieldType.Name
+	options.defaultValue, options.hasDefault = field.Tag.Lookup(defaultTagKey)
+	return options
 }
@@ -0,0 +1,112 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConfigSource type of function that reads actual version of config.
+type ConfigSource func() (Config, error)
+
+// FileSource returns source that reads config from file. Config type is detected by
+// file extension.
+func FileSource(configPath string) ConfigSource {
+	return func() (Config, error) {
+		return ReadConfig(configPath)
+	}
+}
+
+// TypedFileSource returns source that reads config of specified type from file.
+func TypedFileSource(configPath string, configType string) ConfigSource {
+	return func() (Config, error) {
+		return ReadTypedConfig(configPath, configType)
+	}
+}
+
+// HolderValidator type of function that checks value loaded by holder.
+type HolderValidator[T any] func(value *T) error
+
+// Holder keeps value loaded from config and replaces it atomically on reload. Readers
+// may call 'Get' concurrently with reload without locking.
+type Holder[T any] struct {
+	source    ConfigSource
+	settings  LoadSettings
+	path      string
+	validator HolderValidator[T]
+
+	reloadMutex sync.Mutex
+	value       atomic.Pointer[T]
+	lastError   atomic.Pointer[error]
+}
+
+// NewHolder creates holder and loads initial value. Value is loaded from config part by
+// specified path using 'TunedLoadValue' with specified settings. Argument 'validator' may
+// be nil. Holder is not created if initial value cannot be loaded.
+func NewHolder[T any](source ConfigSource, settings LoadSettings, path string,
+	validator HolderValidator[T]) (*Holder[T], error) {
+
+	holder := &Holder[T]{source: source, settings: settings, path: path, validator: validator}
+	if err := holder.Reload(); err != nil {
+		return nil, err
+	}
+	return holder, nil
+}
+
+// Get returns current value. Returned value must not be modified.
+func (h *Holder[T]) Get() *T {
+	return h.value.Load()
+}
+
+// Reload reads config from source, loads and validates new value. Value is replaced only if
+// all steps succeeded, otherwise previous value is kept and error is returned.
+func (h *Holder[T]) Reload() error {
+	h.reloadMutex.Lock()
+	defer h.reloadMutex.Unlock()
+
+	value, err := h.load()
+	h.lastError.Store(&err)
+	if err != nil {
+		return err
+	}
+	h.value.Store(value)
+	return nil
+}
+
+// LastError returns error of last reload or nil if it succeeded.
+func (h *Holder[T]) LastError() error {
+	if err := h.lastError.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (h *Holder[T]) load() (*T, error) {
+	config, err := h.source()
+	if err != nil {
+		return nil, err
+	}
+	value := new(T)
+	if err = TunedLoadValue(config, h.settings, h.path, value); err != nil {
+		return nil, err
+	}
+	if h.validator != nil {
+		if err = h.validator(value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,138 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type holderData struct {
+	Host string `config:"host"`
+	Port int    `config:"port"`
+}
+
+var (
+	errorForTestHolderValidation = errors.New("Port must be positive")
+)
+
+func validateHolderData(value *holderData) error {
+	if value.Port <= 0 {
+		return errorForTestHolderValidation
+	}
+	return nil
+}
+
+func writeConfigFile(t *testing.T, configPath string, data string) {
+	err := os.WriteFile(configPath, []byte(data), 0644)
+	require.NoError(t, err, "Cannot write config file")
+}
+
+// Tests.
+func TestHolderReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"server": {"host": "localhost", "port": 8080}}`)
+
+	holder, err := NewHolder(FileSource(configPath), GetDefaultLoadSettings(false), "/server",
+		validateHolderData)
+	require.NoError(t, err, "Cannot create holder")
+	require.Equal(t, &holderData{Host: "localhost", Port: 8080}, holder.Get())
+
+	writeConfigFile(t, configPath, `{"server": {"host": "example.com", "port": 9090}}`)
+	require.NoError(t, holder.Reload(), "Cannot reload value")
+	require.Equal(t, &holderData{Host: "example.com", Port: 9090}, holder.Get())
+	require.NoError(t, holder.LastError())
+}
+
+func TestHolderKeepsValueOnFailedReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, configPath, "host: localhost\nport: 8080\n")
+
+	holder, err := NewHolder(FileSource(configPath), GetDefaultLoadSettings(false), "/",
+		validateHolderData)
+	require.NoError(t, err, "Cannot create holder")
+	previous := holder.Get()
+
+	writeConfigFile(t, configPath, "host: [localhost\n")
+	require.Error(t, holder.Reload(), "Incorrect config loaded successfully")
+	require.Error(t, holder.LastError())
+	require.True(t, previous == holder.Get(), "Value must be unchanged")
+
+	writeConfigFile(t, configPath, "host: localhost\nport: -1\n")
+	require.EqualError(t, holder.Reload(), errorForTestHolderValidation.Error())
+	require.True(t, previous == holder.Get(), "Value must be unchanged")
+
+	writeConfigFile(t, configPath, "host: localhost\nport: 80\n")
+	require.NoError(t, holder.Reload(), "Cannot reload value")
+	require.NoError(t, holder.LastError())
+	require.Equal(t, &holderData{Host: "localhost", Port: 80}, holder.Get())
+}
+
+func TestHolderWithoutValidator(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	writeConfigFile(t, configPath, "host=localhost\nport=-1")
+
+	holder, err := NewHolder[holderData](TypedFileSource(configPath, INI),
+		GetDefaultLoadSettings(false), "/", nil)
+	require.NoError(t, err, "Cannot create holder")
+	require.Equal(t, &holderData{Host: "localhost", Port: -1}, holder.Get())
+}
+
+func TestHolderConcurrentAccess(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "localhost", "port": 8080}`)
+
+	holder, err := NewHolder[holderData](FileSource(configPath), GetDefaultLoadSettings(false),
+		"/", nil)
+	require.NoError(t, err, "Cannot create holder")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = holder.Reload()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if value := holder.Get(); value == nil || value.Port != 8080 {
+					t.Errorf("Incorrect value %v", value)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHolderInitialLoadError(t *testing.T) {
+	_, err := NewHolder[holderData](FileSource("/incorrectConfigPath.json"),
+		GetDefaultLoadSettings(false), "/", nil)
+	require.Error(t, err)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "localhost"}`)
+
+	_, err = NewHolder[holderData](FileSource(configPath), GetDefaultLoadSettings(false), "/", nil)
+	require.ErrorIs(t, err, ErrorNotFound)
+}
@@ -0,0 +1,265 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ListSettings is settings of splitting string values into list elements.
+type ListSettings struct {
+	// Elements may be enclosed in double or single quotes, delimiters and whitespaces inside
+	// quotes are kept. Quote opens only at the beginning of element and only if it is closed
+	// at the end of element, other quotes (like apostrophes in "'tis" or "don't") are kept
+	// as is.
+	Quotes bool
+	// Backslash escapes delimiter, quote or backslash, it is kept before other symbols.
+	Escapes bool
+	// Whitespaces around elements are trimmed.
+	TrimSpace bool
+	// Empty elements are dropped (quoted empty elements are kept).
+	DropEmpty bool
+}
+
+var (
+	// defaultListSettings is used by list getters of configs. Escapes are disabled, so
+	// backslashes of values (like Windows paths) are kept.
+	defaultListSettings = ListSettings{Quotes: true, TrimSpace: true}
+)
+
+// GetDefaultListSettings returns settings that support quotes, escapes, trim whitespaces and
+// drop empty elements.
+func GetDefaultListSettings() *ListSettings {
+	return &ListSettings{Quotes: true, Escapes: true, TrimSpace: true, DropEmpty: true}
+}
+
+// SplitList splits string into list elements by delimiter. Empty string is split into empty
+// list, empty delimiter does not split string. Unmatched quotes are kept as is.
+func SplitList(data string, delim string, settings ListSettings) ([]string, error) {
+	elements := make([]string, 0)
+	if len(data) == 0 || (settings.TrimSpace && len(strings.TrimSpace(data)) == 0) {
+		return elements, nil
+	}
+
+	var element strings.Builder
+	// Length of element that is kept after trimming and whether element contains quotes.
+	kept, quoted := 0, false
+	addElement := func() {
+		value := element.String()
+		if settings.TrimSpace {
+			value = value[:kept]
+		}
+		if len(value) != 0 || quoted || !settings.DropEmpty {
+			elements = append(elements, value)
+		}
+		element.Reset()
+		kept, quoted = 0, false
+	}
+
+	var quote rune
+	for i := 0; i < len(data); {
+		symbol, size := utf8.DecodeRuneInString(data[i:])
+		switch {
+		case settings.Escapes && symbol == '\\' && i+size < len(data):
+			escaped := data[i+size:]
+			if length := escapedLength(escaped, delim, settings.Quotes); length > 0 {
+				element.WriteString(escaped[:length])
+				i += size + length
+				kept = element.Len()
+				continue
+			}
+			element.WriteRune(symbol)
+		case quote != 0:
+			if symbol == quote {
+				quote = 0
+			} else {
+				element.WriteRune(symbol)
+			}
+			kept = element.Len()
+		case settings.Quotes && (symbol == '"' || symbol == '\'') && element.Len() == 0 && !quoted &&
+			isQuoteClosed(data[i+size:], symbol, delim, settings):
+			quote, quoted = symbol, true
+		case len(delim) != 0 && strings.HasPrefix(data[i:], delim):
+			addElement()
+			i += len(delim)
+			continue
+		case settings.TrimSpace && unicode.IsSpace(symbol):
+			if element.Len() != 0 || quoted {
+				element.WriteRune(symbol)
+			}
+		default:
+			element.WriteRune(symbol)
+			kept = element.Len()
+		}
+		i += size
+	}
+	addElement()
+	return elements, nil
+}
+
+// isQuoteClosed checks whether data contains quote that closes element, i.e. it is followed
+// by delimiter or end of data (whitespaces are skipped).
+func isQuoteClosed(data string, quote rune, delim string, settings ListSettings) bool {
+	for i := 0; i < len(data); {
+		symbol, size := utf8.DecodeRuneInString(data[i:])
+		if settings.Escapes && symbol == '\\' {
+			size += escapedLength(data[i+size:], delim, settings.Quotes)
+		} else if symbol == quote {
+			after := data[i+size:]
+			rest := strings.TrimLeftFunc(after, unicode.IsSpace)
+			if len(rest) == 0 ||
+				(len(delim) != 0 && (strings.HasPrefix(after, delim) || strings.HasPrefix(rest, delim))) {
+				return true
+			}
+		}
+		i += size
+	}
+	return false
+}
+
+// escapedLength returns length of delimiter, quote or backslash escaped by backslash at the
+// beginning of data or 0 if backslash does not escape anything.
+func escapedLength(data string, delim string, quotes bool) int {
+	switch {
+	case len(delim) != 0 && strings.HasPrefix(data, delim):
+		return len(delim)
+	case data[0] == '\\' || (quotes && (data[0] == '"' || data[0] == '\'')):
+		return 1
+	}
+	return 0
+}
+
+// ExpandRanges replaces numeric ranges like '8000-8010' by all numbers of range (both ends
+// are included). Other elements are kept as is.
+func ExpandRanges(elements []string) ([]string, error) {
+	result := make([]string, 0, len(elements))
+	for _, element := range elements {
+		first, last, isRange := parseRange(element)
+		if !isRange {
+			result = append(result, element)
+			continue
+		}
+		if first > last || uint64(last)-uint64(first) >= maxRangeLength {
+			return nil, fmt.Errorf("%w: range %q", ErrorIncorrectValueFormat, element)
+		}
+		for number := first; ; number++ {
+			result = append(result, strconv.FormatInt(number, 10))
+			if number == last {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+const (
+	// maxRangeLength limits number of elements of single range.
+	maxRangeLength = 1 << 16
+)
+
+// parseRange parses range of integers, sign of the first number is not treated as separator.
+func parseRange(data string) (first int64, last int64, isRange bool) {
+	separator := strings.IndexByte(strings.TrimPrefix(data, "-"), '-')
+	if separator < 0 {
+		return 0, 0, false
+	}
+	separator += len(data) - len(strings.TrimPrefix(data, "-"))
+	first, firstErr := parseXMLInt(strings.TrimSpace(data[:separator]))
+	last, lastErr := parseXMLInt(strings.TrimSpace(data[separator+1:]))
+	return first, last, firstErr == nil && lastErr == nil
+}
+
+// getIntRanges returns list of int by specified path, ranges of list elements are expanded
+// by 'ExpandRanges'.
+func getIntRanges(c Config, path string, delim string) ([]int64, error) {
+	elements, err := c.GetStrings(path, delim)
+	if err == nil {
+		elements, err = ExpandRanges(elements)
+	}
+	if err != nil {
+		return nil, setPathErrorType(wrapPathError(c, path, "", err), "[]int64")
+	}
+	result := make([]int64, 0, len(elements))
+	for _, element := range elements {
+		parsed, err := parseXMLInt(strings.TrimSpace(element))
+		if err != nil {
+			return nil, setPathErrorType(wrapPathError(c, path, element, err), "[]int64")
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
+
+// isSplitByLoader checks whether slice with elements of type is loaded from string value by
+// 'loadListValue' even without list settings.
+func isSplitByLoader(elementType reflect.Type) bool {
+	return elementType.Kind() == reflect.Bool || isInteger(elementType)
+}
+
+func isInteger(valueType reflect.Type) bool {
+	switch valueType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// listValueConfig is implemented by configs that store lists of strings without delimiters
+// (like repeated keys of INI configs), such lists are not split into elements.
+type listValueConfig interface {
+	listValue(path string) ([]string, bool)
+}
+
+// loadListValue loads slice from string value split by list settings (or by default settings
+// of list getters). Elements are loaded in the same way as single values.
+func loadListValue(c Config, settings LoadSettings, path string, data string,
+	value reflect.Value) (reflect.Value, error) {
+
+	listSettings := defaultListSettings
+	if settings.List != nil {
+		listSettings = *settings.List
+	}
+	elements, err := SplitList(data, settings.Delim, listSettings)
+	if err != nil {
+		return reflect.ValueOf(nil), wrapPathError(c, path, data, err)
+	}
+	return loadListElements(c, settings, path, elements, value)
+}
+
+// loadListElements loads slice from list elements. Ranges are expanded for integer elements.
+func loadListElements(c Config, settings LoadSettings, path string, elements []string,
+	value reflect.Value) (reflect.Value, error) {
+
+	if isInteger(value.Type().Elem()) {
+		expanded, err := ExpandRanges(elements)
+		if err != nil {
+			return reflect.ValueOf(nil), wrapPathError(c, path, strings.Join(elements, settings.Delim), err)
+		}
+		elements = expanded
+	}
+	result := reflect.MakeSlice(value.Type(), len(elements), len(elements))
+	for i, element := range elements {
+		if err := loadStringValue(settings, path, element, result.Index(i)); err != nil {
+			return reflect.ValueOf(nil), wrapPathError(c, path, element, err)
+		}
+	}
+	return result, nil
+}
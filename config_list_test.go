@@ -0,0 +1,127 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkSplitList(t *testing.T, data string, delim string, settings ListSettings, expected ...string) {
+	elements, err := SplitList(data, delim, settings)
+	require.NoError(t, err, "Cannot split '%s'", data)
+	if expected == nil {
+		expected = []string{}
+	}
+	require.Equal(t, expected, elements, data)
+}
+
+// Tests.
+func TestSplitList(t *testing.T) {
+	settings := *GetDefaultListSettings()
+	checkSplitList(t, `"a b", c`, ",", settings, "a b", "c")
+	checkSplitList(t, `'x, y' , "" ,, z`, ",", settings, "x, y", "", "z")
+	checkSplitList(t, `a\,b, c\"d, e\\f, g\h`, ",", settings, `a,b`, `c"d`, `e\f`, `g\h`)
+	checkSplitList(t, `  "  padded "  x`, " ", settings, "  padded ", "x")
+	checkSplitList(t, "one::two::", "::", settings, "one", "two")
+	checkSplitList(t, "  ", ",", settings)
+	checkSplitList(t, "a,b", "", settings, "a,b")
+
+	checkSplitList(t, ` a , "b" ,`, ",", ListSettings{}, " a ", ` "b" `, "")
+	checkSplitList(t, ` a , "b" ,`, ",", defaultListSettings, "a", "b", "")
+	checkSplitList(t, ` a ,"b" ,`, ",", ListSettings{Quotes: true, DropEmpty: true}, " a ", "b ")
+	checkSplitList(t, `don't, it's`, ",", settings, "don't", "it's")
+
+	checkSplitList(t, `"a, b`, ",", settings, `"a`, "b")
+	checkSplitList(t, `'tis, 'twas, "a"b`, ",", settings, "'tis", "'twas", `"a"b`)
+	checkSplitList(t, `"a\", b", c`, ",", settings, `a", b`, "c")
+}
+
+func TestListGettersUseTokenizer(t *testing.T) {
+	for configType, data := range map[string]string{
+		XML: `<hosts> db1 , "db,2" ,db3</hosts><names>"a b", c</names><shares>\\srv\share,x</shares>` +
+			`<words>'tis, don't</words>`,
+		INI: "hosts = ` db1 , \"db,2\" ,db3`\nnames = `\"a b\", c`\nshares = \\\\srv\\share,x\nwords = 'tis, don't\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		hosts, err := config.GetStrings("/hosts", ",")
+		require.NoError(t, err, configType)
+		require.Equal(t, []string{"db1", "db,2", "db3"}, hosts, configType)
+
+		names, err := config.GetStrings("/names", ",")
+		require.NoError(t, err, configType)
+		require.Equal(t, []string{"a b", "c"}, names, configType)
+
+		words, err := config.GetStrings("/words", ",")
+		require.NoError(t, err, configType)
+		require.Equal(t, []string{"'tis", "don't"}, words, "Unmatched quotes must be kept (%s)", configType)
+
+		shares, err := config.GetStrings("/shares", ",")
+		require.NoError(t, err, configType)
+		require.Equal(t, []string{`\\srv\share`, "x"}, shares, "Backslashes must be kept (%s)", configType)
+	}
+}
+
+func TestLoadValueWithListSettings(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"names": "\"Jane Doe\", 'John Smith',, Bob", "ports": "80, 443", "timeouts": ["1s", "2m"]}`,
+		YAML: "names: '\"Jane Doe\", ''John Smith'',, Bob'\nports: 80, 443\ntimeouts: [1s, 2m]\n",
+		XML:  "<names>\"Jane Doe\", 'John Smith',, Bob</names><ports>80, 443</ports><timeouts>1s,2m</timeouts>",
+		INI:  "names = \"Jane Doe\", 'John Smith',, Bob\nports = 80, 443\ntimeouts = 1s,2m\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		settings := GetDefaultLoadSettings(false)
+		settings.Delim = ","
+		settings.List = GetDefaultListSettings()
+
+		var value struct {
+			Names    []string        `config:"names"`
+			Ports    [2]uint16       `config:"ports"`
+			Timeouts []time.Duration `config:"timeouts"`
+			Defaults []int           `config:"defaults" default:"1, '2'"`
+		}
+		require.NoError(t, TunedLoadValue(config, settings, "/", &value), configType)
+		require.Equal(t, []string{"Jane Doe", "John Smith", "Bob"}, value.Names, configType)
+		require.Equal(t, [2]uint16{80, 443}, value.Ports, configType)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, value.Timeouts, configType)
+		require.Equal(t, []int{1, 2}, value.Defaults, configType)
+	}
+}
+
+func TestLoadIncorrectListElement(t *testing.T) {
+	config, err := CreateConfigFromString("[server]\nports = 80, 70000\nhosts = 'db1, db2\n", INI)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.Delim = ","
+	settings.List = GetDefaultListSettings()
+
+	var ports []uint16
+	err = TunedLoadValue(config, settings, "/server/ports", &ports)
+	require.ErrorIs(t, err, ErrorValueOverflow)
+	require.EqualError(t, err, "2:1: Value overflows type by path '/server/ports', value '70000', "+
+		"expected type []uint16")
+
+	var hosts []string
+	err = TunedLoadValue(config, settings, "/server/hosts", &hosts)
+	require.NoError(t, err, "Unmatched quote must be kept")
+	require.Equal(t, []string{"'db1", "db2"}, hosts)
+}
@@ -0,0 +1,135 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// DefaultKeyValueDelimiter default delimiter of key and value in map strings.
+	defaultKeyValueDelimiter = "="
+)
+
+var (
+	// defaultMapSettings is used to split map strings into pairs.
+	defaultMapSettings = ListSettings{Escapes: true, TrimSpace: true, DropEmpty: true}
+)
+
+// GetStringMap returns map from string value like 'env=prod,team=core'. Argument 'pairDelim'
+// is used to split string into pairs, 'kvDelim'--to split pair into key and value.
+func GetStringMap(c Config, path string, pairDelim string, kvDelim string) (value map[string]string, err error) {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = SplitStringMap(data, pairDelim, kvDelim, defaultMapSettings)
+		return err
+	}), "map[string]string")
+}
+
+// SplitStringMap splits string into pairs of keys and values. Pairs are split by list
+// settings, keys and values are trimmed. Key of pair is separated from value by the first
+// delimiter 'kvDelim', so value may contain it.
+func SplitStringMap(data string, pairDelim string, kvDelim string, settings ListSettings) (map[string]string, error) {
+	pairs, err := SplitList(data, pairDelim, settings)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		separator := strings.Index(pair, kvDelim)
+		if len(kvDelim) == 0 || separator < 0 {
+			return nil, fmt.Errorf("%w: pair %q does not contain delimiter %q", ErrorIncorrectValueFormat,
+				pair, kvDelim)
+		}
+		result[strings.TrimSpace(pair[:separator])] = strings.TrimSpace(pair[separator+len(kvDelim):])
+	}
+	return result, nil
+}
+
+// loadMapValue loads map from string value. Pairs are split by 'LoadSettings.Delim' and list
+// settings, keys and values are loaded in the same way as single values. Map is loaded from
+// keys of structured value (like JSON object or INI section) if path addresses it.
+func loadMapValue(c Config, settings LoadSettings, path string, value reflect.Value) (reflect.Value, error) {
+	if keys := getStructuredKeys(c, path); keys != nil {
+		return loadStructuredMapValue(c, settings, path, keys, value)
+	}
+	data, err := c.GetString(path)
+	if err != nil {
+		return reflect.ValueOf(nil), err
+	}
+	listSettings, kvDelim := defaultMapSettings, settings.KeyValueDelim
+	if settings.List != nil {
+		listSettings = *settings.List
+	}
+	if len(kvDelim) == 0 {
+		kvDelim = defaultKeyValueDelimiter
+	}
+	pairs, err := SplitStringMap(data, settings.Delim, kvDelim, listSettings)
+	if err != nil {
+		return reflect.ValueOf(nil), wrapPathError(c, path, data, err)
+	}
+
+	result := reflect.MakeMapWithSize(value.Type(), len(pairs))
+	for key, element := range pairs {
+		loadedKey := reflect.New(value.Type().Key()).Elem()
+		if err = loadStringValue(settings, path, key, loadedKey); err != nil {
+			return reflect.ValueOf(nil), wrapPathError(c, path, key, err)
+		}
+		loadedElement := reflect.New(value.Type().Elem()).Elem()
+		if err = loadStringValue(settings, path, element, loadedElement); err != nil {
+			return reflect.ValueOf(nil), wrapPathError(c, path, element, err)
+		}
+		result.SetMapIndex(loadedKey, loadedElement)
+	}
+	return result, nil
+}
+
+// getStructuredKeys returns keys of value by path if value is structured.
+func getStructuredKeys(c Config, path string) []string {
+	configPart, err := c.GetConfigPart(path)
+	treeConfig, ok := configPart.(valueTreeConfig)
+	if err != nil || !ok {
+		return nil
+	}
+	tree, ok := treeConfig.valueTree().(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// loadStructuredMapValue loads map from keys of structured value, values are loaded in the same
+// way as values of struct fields.
+func loadStructuredMapValue(c Config, settings LoadSettings, path string, keys []string, value reflect.Value) (reflect.Value, error) {
+	result := reflect.MakeMapWithSize(value.Type(), len(keys))
+	for _, key := range keys {
+		keyPath := joinPath(path, key)
+		loadedKey := reflect.New(value.Type().Key()).Elem()
+		if err := loadStringValue(settings, keyPath, key, loadedKey); err != nil {
+			return reflect.ValueOf(nil), wrapPathError(c, keyPath, key, err)
+		}
+		loadedElement, err := loadSingleValue(c, settings, keyPath, reflect.New(value.Type().Elem()).Elem())
+		if err != nil {
+			return reflect.ValueOf(nil), err
+		}
+		result.SetMapIndex(loadedKey, loadedElement)
+	}
+	return result, nil
+}
@@ -0,0 +1,180 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests.
+func TestSplitStringMap(t *testing.T) {
+	value, err := SplitStringMap(`env=prod, team = core,, url=http://host/?a=b, note=a\,b`, ",", "=",
+		defaultMapSettings)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"env": "prod", "team": "core", "url": "http://host/?a=b",
+		"note": "a,b"}, value)
+
+	value, err = SplitStringMap("", ",", "=", defaultMapSettings)
+	require.NoError(t, err)
+	require.Empty(t, value)
+
+	_, err = SplitStringMap("env=prod,team", ",", "=", defaultMapSettings)
+	require.ErrorIs(t, err, ErrorIncorrectValueFormat)
+}
+
+func TestExpandRanges(t *testing.T) {
+	value, err := ExpandRanges([]string{"80", "8000-8003", "-2--1", "-5", "a-b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"80", "8000", "8001", "8002", "8003", "-2", "-1", "-5", "a-b"}, value)
+
+	for _, data := range []string{"10-1", "0-100000", "-9223372036854775808-9223372036854775807"} {
+		_, err = ExpandRanges([]string{data})
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, data)
+	}
+}
+
+func TestGetIntRanges(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"ports": [80, "8000-8002"], "reversed": ["8010-8000"], "words": [80, "http"]}`,
+		YAML: "ports: [80, 8000-8002]\nreversed: [8010-8000]\nwords: [80, http]\n",
+		XML:  "<ports>80,8000-8002</ports><reversed>8010-8000</reversed><words>80,http</words>",
+		INI:  "ports = 80,8000-8002\nreversed = 8010-8000\nwords = 80,http\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		ports, err := config.GetIntRanges("/ports", ",")
+		require.NoError(t, err, configType)
+		require.Equal(t, []int64{80, 8000, 8001, 8002}, ports, configType)
+
+		_, err = config.GetIntRanges("/reversed", ",")
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, configType)
+		var pathError *PathError
+		require.ErrorAs(t, err, &pathError, configType)
+		require.Equal(t, "[]int64", pathError.Type, configType)
+
+		_, err = config.GetIntRanges("/words", ",")
+		require.ErrorAs(t, err, &pathError, configType)
+		require.Equal(t, "/words", pathError.Path, configType)
+		require.Equal(t, "http", pathError.Value, configType)
+
+		_, err = config.GetIntRanges("/absent", ",")
+		require.ErrorIs(t, err, ErrorNotFound, configType)
+	}
+}
+
+func TestGetStringMap(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"labels": "env=prod,team=core"}`,
+		YAML: "labels: env=prod,team=core\n",
+		XML:  "<labels>env=prod,team=core</labels>",
+		INI:  "labels = env=prod,team=core\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		labels, err := GetStringMap(config, "/labels", ",", "=")
+		require.NoError(t, err, configType)
+		require.Equal(t, map[string]string{"env": "prod", "team": "core"}, labels, configType)
+
+		_, err = GetStringMap(config, "/labels", ",", ":")
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, configType)
+		var pathError *PathError
+		require.ErrorAs(t, err, &pathError, configType)
+		require.Equal(t, "map[string]string", pathError.Type, configType)
+	}
+}
+
+func TestLoadMapsAndRanges(t *testing.T) {
+	for configType, data := range map[string]string{
+		XML: "<labels>env=prod team=core</labels><limits>db=1s cache=500ms</limits>" +
+			"<ports>80 8000-8002</ports><ids>7</ids>",
+		INI: "labels = env=prod team=core\nlimits = db=1s cache=500ms\nports = 80 8000-8002\nids = 7\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value struct {
+			Labels map[string]string        `config:"labels"`
+			Limits map[string]time.Duration `config:"limits"`
+			Ports  []uint16                 `config:"ports"`
+			IDs    []int                    `config:"ids"`
+		}
+		require.NoError(t, LoadValue(config, "/", &value), configType)
+		require.Equal(t, map[string]string{"env": "prod", "team": "core"}, value.Labels, configType)
+		require.Equal(t, map[string]time.Duration{"db": time.Second, "cache": 500 * time.Millisecond},
+			value.Limits, configType)
+		require.Equal(t, []uint16{80, 8000, 8001, 8002}, value.Ports, configType)
+		require.Equal(t, []int{7}, value.IDs, configType)
+	}
+
+	config, err := CreateConfigFromString(`{"ports": [80, 443], "labels": "env:prod;team:core"}`, JSON)
+	require.NoError(t, err, "Cannot load json-config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.Delim, settings.KeyValueDelim = ";", ":"
+	var value struct {
+		Ports  []int             `config:"ports"`
+		Labels map[string]string `config:"labels"`
+	}
+	require.NoError(t, TunedLoadValue(config, settings, "/", &value))
+	require.Equal(t, []int{80, 443}, value.Ports)
+	require.Equal(t, map[string]string{"env": "prod", "team": "core"}, value.Labels)
+}
+
+func TestLoadIncorrectMapsAndRanges(t *testing.T) {
+	config, err := CreateConfigFromString("[server]\nlimits = db=fast\nports = 8010-8000\n", INI)
+	require.NoError(t, err, "Cannot load config")
+
+	var limits map[string]time.Duration
+	err = LoadValue(config, "/server/limits", &limits)
+	require.EqualError(t, err, "2:1: time: invalid duration \"fast\" by path '/server/limits', "+
+		"value 'fast', expected type map[string]time.Duration")
+
+	var ports []int
+	err = LoadValue(config, "/server/ports", &ports)
+	require.ErrorIs(t, err, ErrorIncorrectValueFormat)
+
+	var structured map[string]int
+	config, err = CreateConfigFromString(`{"limits": {"db": "fast"}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+	err = LoadValue(config, "/limits", &structured)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+	require.EqualError(t, err, "1:13: Incorrect value type by path '/limits/db', value 'fast', "+
+		"expected type int")
+}
+
+func TestLoadStructuredMaps(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"labels": {"env": "prod", "team": "core"}, "limits": {"db": "5s", "cache": "1m"}}`,
+		YAML: "labels: {env: prod, team: core}\nlimits:\n  db: 5s\n  cache: 1m\n",
+		INI:  "[labels]\nenv = prod\nteam = core\n[limits]\ndb = 5s\ncache = 1m\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value struct {
+			Labels map[string]string        `config:"labels"`
+			Limits map[string]time.Duration `config:"limits"`
+		}
+		require.NoError(t, LoadValue(config, "/", &value), configType)
+		require.Equal(t, map[string]string{"env": "prod", "team": "core"}, value.Labels, configType)
+		require.Equal(t, map[string]time.Duration{"db": 5 * time.Second, "cache": time.Minute},
+			value.Limits, configType)
+	}
+}
@@ -0,0 +1,79 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// normalizableConfig is implemented by configs that support lookup by normalized keys.
+type normalizableConfig interface {
+	withNormalizedKeys() Config
+}
+
+// NormalizeKeys returns config that matches keys of paths with keys of config after
+// normalization (see 'NormalizeKey'), so path '/maxConnections' addresses key
+// 'max_connections'. Lookup fails with 'ErrorAmbiguousKey' if several keys of config match
+// key of path. Config parts of returned config normalize keys too.
+func NormalizeKeys(c Config) Config {
+	if config, ok := c.(normalizableConfig); ok {
+		return config.withNormalizedKeys()
+	}
+	return c
+}
+
+// NormalizeKey returns key in lower case without underscores and hyphens, so keys in camel,
+// snake and kebab cases ('maxConnections', 'max_connections', 'max-connections') and name of
+// structure field ('MaxConnections') are the same.
+func NormalizeKey(key string) string {
+	return strings.Map(func(symbol rune) rune {
+		if symbol == '_' || symbol == '-' {
+			return -1
+		}
+		return unicode.ToLower(symbol)
+	}, key)
+}
+
+// findNormalizedKey returns the only key that matches name after normalization.
+func findNormalizedKey(keys []string, name string) (string, error) {
+	normalized := NormalizeKey(name)
+	var matched []string
+	for _, key := range keys {
+		if NormalizeKey(key) == normalized {
+			matched = append(matched, key)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return "", ErrorNotFound
+	case 1:
+		return matched[0], nil
+	}
+	sort.Strings(matched)
+	return "", fmt.Errorf("%w: keys %q match %q", ErrorAmbiguousKey, matched, name)
+}
+
+// sortedKeys returns sorted keys of map.
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type normalizedServerData struct {
+	Server struct {
+		MaxConnections int    `config:"maxConnections"`
+		ReadTimeout    string `config:"read-timeout"`
+		HTTPPort       uint16
+	}
+}
+
+// Tests.
+func TestNormalizeKey(t *testing.T) {
+	for _, key := range []string{"maxConnections", "max_connections", "max-connections",
+		"MaxConnections", "MAX_CONNECTIONS"} {
+		require.Equal(t, "maxconnections", NormalizeKey(key), key)
+	}
+	require.Equal(t, "httpport", NormalizeKey("HTTPPort"))
+	require.Equal(t, NormalizeKey("http_port"), NormalizeKey("HTTPPort"))
+}
+
+func TestLoadValueWithNormalizedKeys(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"server": {"max_connections": 100, "ReadTimeout": "5s", "http-port": 8080}}`,
+		YAML: "SERVER:\n  max-connections: 100\n  read_timeout: 5s\n  httpPort: 8080\n",
+		XML: "<Server><max_connections>100</max_connections><read_timeout>5s</read_timeout>" +
+			"<http-port>8080</http-port></Server>",
+		INI: "[server]\nMAX_CONNECTIONS = 100\nreadTimeout = 5s\nhttp_port = 8080\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		settings := GetDefaultLoadSettings(false)
+		settings.NormalizeKeys = true
+		var value normalizedServerData
+		require.NoError(t, TunedLoadValue(config, settings, "/", &value), configType)
+		require.Equal(t, 100, value.Server.MaxConnections, configType)
+		require.Equal(t, "5s", value.Server.ReadTimeout, configType)
+		require.Equal(t, uint16(8080), value.Server.HTTPPort, configType)
+
+		err = LoadValue(config, "/", &normalizedServerData{})
+		require.ErrorIs(t, err, ErrorNotFound, "Keys must be matched exactly by default (%s)", configType)
+	}
+}
+
+func TestNormalizedConfigParts(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: "{\"db_settings\": {\n\"replica-host\": \"db2\"}}",
+		YAML: "db_settings:\n  replica-host: db2\n",
+		XML:  "<db_settings>\n<replica-host>db2</replica-host></db_settings>",
+		INI:  "[db_settings]\nreplica-host = db2\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+		config = NormalizeKeys(config)
+
+		part, err := config.GetConfigPart("/dbSettings")
+		require.NoError(t, err, configType)
+		host, err := part.GetString("/ReplicaHost")
+		require.NoError(t, err, "Config part must normalize keys (%s)", configType)
+		require.Equal(t, "db2", host, configType)
+
+		position, err := config.Position("/DbSettings/replicaHost")
+		require.NoError(t, err, configType)
+		require.Equal(t, 2, position.Line, configType)
+	}
+}
+
+func TestAmbiguousNormalizedKeys(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"max_connections": 1, "maxConnections": 2}`,
+		YAML: "max_connections: 1\nmaxConnections: 2\n",
+		XML:  "<max_connections>1</max_connections><maxConnections>2</maxConnections>",
+		INI:  "max_connections = 1\nmaxConnections = 2\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		value, err := config.GetInt("/maxConnections")
+		require.NoError(t, err, configType)
+		require.Equal(t, int64(2), value, configType)
+
+		_, err = NormalizeKeys(config).GetInt("/maxConnections")
+		require.ErrorIs(t, err, ErrorAmbiguousKey, configType)
+		require.Contains(t, err.Error(), `Ambiguous key: keys ["maxConnections" "max_connections"] match `+
+			`"maxConnections" by path '/maxConnections'`, configType)
+	}
+}
@@ -0,0 +1,128 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Position describes location of value in config source. Position of value is position of
+// its key (element for XML), lines and columns are counted from 1.
+type Position struct {
+	// Path to config file (empty if config is not read from file).
+	File string
+	// Line number (0 if position is unknown).
+	Line int
+	// Column number in bytes (0 if it is unknown).
+	Column int
+}
+
+// IsValid returns true if position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns position in format 'file:line:column', unknown parts are omitted.
+func (p Position) String() string {
+	result := p.File
+	if p.IsValid() {
+		if len(result) != 0 {
+			result += ":"
+		}
+		result += strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			result += ":" + strconv.Itoa(p.Column)
+		}
+	}
+	return result
+}
+
+// ParseError is returned when config cannot be parsed.
+type ParseError struct {
+	// Position of error in config source.
+	Position Position
+	// Underlying cause of error.
+	Err error
+}
+
+// Error returns description of error.
+func (e *ParseError) Error() string {
+	if position := e.Position.String(); len(position) != 0 {
+		return fmt.Sprintf("%s: %s", position, e.Err)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying cause of error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Position helpers.
+
+// lineIndex converts offsets in config source to lines and columns.
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	index := lineIndex{0}
+	for offset, symbol := range data {
+		if symbol == '\n' {
+			index = append(index, offset+1)
+		}
+	}
+	return index
+}
+
+func (index lineIndex) Position(offset int) Position {
+	line := sort.Search(len(index), func(i int) bool { return index[i] > offset })
+	return Position{Line: line, Column: offset - index[line-1] + 1}
+}
+
+// positionIndex contains positions of values by absolute paths.
+type positionIndex map[string]Position
+
+// findPosition returns position of value by path relative to config part by prefix.
+func findPosition(c Config, positions positionIndex, prefix string, path string) (Position, error) {
+	position, exist := positions[concatPaths(prefix, path)]
+	if !exist {
+		return Position{}, &PathError{Path: joinPath(path), Err: ErrorNotFound}
+	}
+	if fileConfig, ok := c.(sourceFileConfig); ok {
+		position.File = fileConfig.sourceFile()
+	}
+	return position, nil
+}
+
+// concatPaths joins paths into normalized absolute path.
+func concatPaths(paths ...string) string {
+	return joinPath(splitPath(strings.Join(paths, pathDelimiter))...)
+}
+
+var (
+	lineInErrorRegexp = regexp.MustCompile(`line (\d+)`)
+)
+
+// newParseErrorFromMessage creates parse error with line found in message of error.
+func newParseErrorFromMessage(err error) error {
+	parseError := &ParseError{Err: err}
+	if match := lineInErrorRegexp.FindStringSubmatch(err.Error()); match != nil {
+		parseError.Position.Line, _ = strconv.Atoi(match[1])
+	}
+	return parseError
+}
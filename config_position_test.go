@@ -0,0 +1,191 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	positionJSONConfig = `{
+  "server": {
+    "host": "localhost",
+    "ports": [80, {"port": 443}]
+  }
+}`
+	positionYAMLConfig = `defaults: &defaults
+  timeout: 5s
+server:
+  <<: *defaults
+  host: localhost
+  ports: [80, 443]
+`
+	positionXMLConfig = `<?xml version="1.0"?>
+<server
+    name="main">
+  <host>localhost</host>
+  <ports>80 443</ports>
+</server>`
+	positionINIConfig = `name = main
+; comment
+
+[server]
+  host = localhost
+ports: 80 443
+`
+)
+
+func checkPosition(t *testing.T, config Config, path string, line, column int) {
+	position, err := config.Position(path)
+	require.NoError(t, err, "Cannot get position of '%s'", path)
+	require.Equal(t, Position{Line: line, Column: column}, position, path)
+}
+
+func checkParseError(t *testing.T, data string, configType string, line, column int) {
+	_, err := CreateConfigFromString(data, configType)
+	var parseError *ParseError
+	require.ErrorAs(t, err, &parseError, configType)
+	require.Equal(t, Position{Line: line, Column: column}, parseError.Position, configType)
+}
+
+// Tests.
+func TestPositionString(t *testing.T) {
+	require.Equal(t, "config.json:2:5", Position{File: "config.json", Line: 2, Column: 5}.String())
+	require.Equal(t, "config.json:2", Position{File: "config.json", Line: 2}.String())
+	require.Equal(t, "2:5", Position{Line: 2, Column: 5}.String())
+	require.Equal(t, "config.json", Position{File: "config.json"}.String())
+	require.Equal(t, "", Position{}.String())
+	require.False(t, Position{Column: 5}.IsValid())
+}
+
+func TestJsonPosition(t *testing.T) {
+	config, err := CreateConfigFromString(positionJSONConfig, JSON)
+	require.NoError(t, err, "Cannot parse json-config")
+
+	checkPosition(t, config, "/", 1, 1)
+	checkPosition(t, config, "/server", 2, 3)
+	checkPosition(t, config, "/server/host", 3, 5)
+	checkPosition(t, config, "/server/ports", 4, 5)
+
+	configPart, err := config.GetConfigPart("/server")
+	require.NoError(t, err, "Cannot get config part")
+	checkPosition(t, configPart, "/host", 3, 5)
+	checkPosition(t, configPart, "/", 2, 3)
+
+	_, err = config.Position("/server/ports/port")
+	require.ErrorIs(t, err, ErrorNotFound)
+	_, err = config.Position("/server/absent")
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestYamlPosition(t *testing.T) {
+	config, err := CreateConfigFromString(positionYAMLConfig, YAML)
+	require.NoError(t, err, "Cannot parse yaml-config")
+
+	checkPosition(t, config, "/", 1, 1)
+	checkPosition(t, config, "/server", 3, 1)
+	checkPosition(t, config, "/server/host", 5, 3)
+	checkPosition(t, config, "/server/ports", 6, 3)
+	checkPosition(t, config, "/server/timeout", 2, 3)
+
+	configPart, err := config.GetConfigPart("/server")
+	require.NoError(t, err, "Cannot get config part")
+	checkPosition(t, configPart, "/host", 5, 3)
+
+	_, err = config.Position("/server/absent")
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestXmlPosition(t *testing.T) {
+	config, err := CreateConfigFromString(positionXMLConfig, XML)
+	require.NoError(t, err, "Cannot parse xml-config")
+
+	checkPosition(t, config, "/server", 2, 1)
+	checkPosition(t, config, "/server/@name", 3, 5)
+	checkPosition(t, config, "/server/host", 4, 3)
+	checkPosition(t, config, "/server/ports", 5, 3)
+
+	configPart, err := config.GetConfigPart("/server")
+	require.NoError(t, err, "Cannot get config part")
+	checkPosition(t, configPart, "/", 2, 1)
+	checkPosition(t, configPart, "/@name", 3, 5)
+	checkPosition(t, configPart, "/host", 4, 3)
+
+	_, err = config.Position("/")
+	require.ErrorIs(t, err, ErrorNotFound)
+	_, err = config.Position("/server/@absent")
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestIniPosition(t *testing.T) {
+	config, err := CreateConfigFromString(positionINIConfig, INI)
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	checkPosition(t, config, "/name", 1, 1)
+	checkPosition(t, config, "/server", 4, 1)
+	checkPosition(t, config, "/server/host", 5, 3)
+	checkPosition(t, config, "/server/ports", 6, 1)
+
+	configPart, err := config.GetConfigPart("/server")
+	require.NoError(t, err, "Cannot get config part")
+	checkPosition(t, configPart, "/", 4, 1)
+	checkPosition(t, configPart, "/host", 5, 3)
+
+	keyPart, err := configPart.GetConfigPart("/ports")
+	require.NoError(t, err, "Cannot get config part")
+	checkPosition(t, keyPart, "/", 6, 1)
+
+	_, err = config.Position("/")
+	require.ErrorIs(t, err, ErrorNotFound)
+	_, err = config.Position("/server/absent")
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestPositionContainsFileName(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.ini")
+	writeConfigFile(t, configPath, positionINIConfig)
+
+	config, err := ReadConfig(configPath)
+	require.NoError(t, err, "Cannot read config")
+
+	configPart, err := config.GetConfigPart("/server")
+	require.NoError(t, err, "Cannot get config part")
+
+	position, err := configPart.Position("/host")
+	require.NoError(t, err, "Cannot get position")
+	require.Equal(t, Position{File: configPath, Line: 5, Column: 3}, position)
+}
+
+func TestParseErrors(t *testing.T) {
+	checkParseError(t, "{\n  \"host\": \"localhost\",\n  \"port\" 80\n}", JSON, 3, 10)
+	checkParseError(t, "host: localhost\n  port: 80\n", YAML, 2, 3)
+	checkParseError(t, "<server>\n  <host>localhost</port>\n</server>", XML, 2, 18)
+	checkParseError(t, "[server]\nhost = localhost\n  port\n", INI, 3, 3)
+	checkParseError(t, "[server\nhost = localhost\n", INI, 1, 1)
+}
+
+func TestParseErrorContainsFileName(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, "{\n  \"host\": localhost\n}")
+
+	_, err := ReadConfig(configPath)
+	var parseError *ParseError
+	require.ErrorAs(t, err, &parseError)
+	require.Equal(t, Position{File: configPath, Line: 2, Column: 11}, parseError.Position)
+	require.EqualError(t, err, configPath+":2:11: invalid character 'l' looking for beginning of value")
+}
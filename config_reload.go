@@ -0,0 +1,232 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Errors returned from reloader.
+var (
+	ErrorReloadRateLimited = errors.New("Reload rate limited")
+)
+
+// ReloadSettings is settings that used to reload config.
+type ReloadSettings struct {
+	// Validator checks new config before it replaces current one. May be nil.
+	Validator func(config Config) error
+	// BeforeReload is called before reading new config. Reload is canceled if it returns
+	// error. May be nil.
+	BeforeReload func(current Config) error
+	// AfterReload is called after each reload attempt with current config, new config (nil
+	// if reload failed) and error of reload. May be nil.
+	AfterReload func(old Config, new Config, err error)
+	// Minimal interval after successful reload. Reloads requested more often return
+	// 'ErrorReloadRateLimited' and are coalesced into single reload performed when interval
+	// ends. Failed reloads and creation of reloader do not delay next reload.
+	MinInterval time.Duration
+	// Notifier that receives changes between old and new configs. May be nil.
+	Notifier *ChangeNotifier
+}
+
+// Reloader keeps actual version of config and replaces it on reload. Readers may call
+// 'Config' concurrently with reload without locking.
+type Reloader struct {
+	source   ConfigSource
+	settings ReloadSettings
+
+	reloadMutex   sync.Mutex
+	config        atomic.Value
+	lastReload    time.Time
+	pendingReload *time.Timer
+
+	signalsMutex sync.Mutex
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+// configHolder allows to store configs of different types in atomic.Value.
+type configHolder struct {
+	config Config
+}
+
+// NewReloader creates reloader and reads initial version of config. Initial config is
+// validated by settings validator, hooks are not called.
+func NewReloader(source ConfigSource, settings ReloadSettings) (*Reloader, error) {
+	reloader := &Reloader{source: source, settings: settings}
+	config, err := reloader.read()
+	if err != nil {
+		return nil, err
+	}
+	reloader.config.Store(configHolder{config: config})
+	return reloader, nil
+}
+
+// ReloadOnSIGHUP creates reloader and starts to reload config on each SIGHUP signal.
+// Use 'FileSource' or 'TypedFileSource' to re-read the same file as 'ReadConfig' or
+// 'ReadTypedConfig'.
+func ReloadOnSIGHUP(source ConfigSource, settings ReloadSettings) (*Reloader, error) {
+	reloader, err := NewReloader(source, settings)
+	if err != nil {
+		return nil, err
+	}
+	reloader.WatchSignals(syscall.SIGHUP)
+	return reloader, nil
+}
+
+// Config returns current version of config.
+func (r *Reloader) Config() Config {
+	return r.config.Load().(configHolder).config
+}
+
+// Reload reads and validates new version of config and replaces current one with it. On
+// fail current config is kept and error is returned.
+func (r *Reloader) Reload() error {
+	r.reloadMutex.Lock()
+	defer r.reloadMutex.Unlock()
+
+	return r.reloadAndNotify()
+}
+
+// reloadAndNotify reloads config and calls hooks. Reload mutex must be locked.
+func (r *Reloader) reloadAndNotify() error {
+	r.cancelPendingReload()
+	current := r.Config()
+	config, err := r.reload(current)
+	if r.settings.AfterReload != nil {
+		r.settings.AfterReload(current, config, err)
+	}
+	if err != nil {
+		return err
+	}
+	if r.settings.Notifier != nil {
+		r.settings.Notifier.Notify(current, config)
+	}
+	return nil
+}
+
+// WatchSignals starts to reload config on each of specified signals (SIGHUP if no signals
+// are specified). Previous watching is stopped.
+func (r *Reloader) WatchSignals(signals ...os.Signal) {
+	r.signalsMutex.Lock()
+	defer r.signalsMutex.Unlock()
+
+	r.stopWatching()
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, signals...)
+
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	r.stop, r.stopped = stop, stopped
+	go func() {
+		defer close(stopped)
+		defer signal.Stop(signalChannel)
+		for {
+			select {
+			case <-signalChannel:
+				// Errors are passed to AfterReload hook.
+				_ = r.Reload()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching signals and cancels delayed reload. Config may still be reloaded by
+// 'Reload' call.
+func (r *Reloader) Stop() {
+	r.signalsMutex.Lock()
+	defer r.signalsMutex.Unlock()
+
+	r.stopWatching()
+
+	r.reloadMutex.Lock()
+	defer r.reloadMutex.Unlock()
+	r.cancelPendingReload()
+}
+
+// Reloader helpers.
+func (r *Reloader) stopWatching() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.stopped
+		r.stop, r.stopped = nil, nil
+	}
+}
+
+func (r *Reloader) reload(current Config) (Config, error) {
+	if !r.lastReload.IsZero() && time.Since(r.lastReload) < r.settings.MinInterval {
+		r.delayReload(r.settings.MinInterval - time.Since(r.lastReload))
+		return nil, ErrorReloadRateLimited
+	}
+
+	if r.settings.BeforeReload != nil {
+		if err := r.settings.BeforeReload(current); err != nil {
+			return nil, err
+		}
+	}
+	config, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	r.config.Store(configHolder{config: config})
+	r.lastReload = time.Now()
+	return config, nil
+}
+
+// delayReload schedules reload that replaces previously delayed one.
+func (r *Reloader) delayReload(delay time.Duration) {
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		r.reloadMutex.Lock()
+		defer r.reloadMutex.Unlock()
+		// Reload may be already performed or canceled while timer was waiting for mutex.
+		if r.pendingReload == timer {
+			// Errors are passed to AfterReload hook.
+			_ = r.reloadAndNotify()
+		}
+	})
+	r.pendingReload = timer
+}
+
+// cancelPendingReload cancels reload delayed by rate limit, so requests are coalesced.
+func (r *Reloader) cancelPendingReload() {
+	if r.pendingReload != nil {
+		r.pendingReload.Stop()
+		r.pendingReload = nil
+	}
+}
+
+func (r *Reloader) read() (Config, error) {
+	config, err := r.source()
+	if err != nil {
+		return nil, err
+	}
+	if r.settings.Validator != nil {
+		if err = r.settings.Validator(config); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,82 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sendSIGHUP(t *testing.T, reloaded chan error) error {
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	require.NoError(t, err, "Cannot send signal")
+
+	select {
+	case err = <-reloaded:
+		return err
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Config is not reloaded on signal")
+	}
+	return nil
+}
+
+// Tests.
+func TestReloadOnSIGHUP(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, configPath, "host: db1\n")
+
+	reloaded := make(chan error, 1)
+	reloader, err := ReloadOnSIGHUP(FileSource(configPath), ReloadSettings{
+		Validator: validateReloadedConfig,
+		AfterReload: func(old, new Config, err error) {
+			reloaded <- err
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+	defer reloader.Stop()
+
+	writeConfigFile(t, configPath, "host: db2\n")
+	require.NoError(t, sendSIGHUP(t, reloaded), "Cannot reload config")
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+
+	writeConfigFile(t, configPath, "port: 5432\n")
+	require.EqualError(t, sendSIGHUP(t, reloaded), errorForTestReloadValidation.Error())
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+}
+
+func TestReloadOnSIGHUPRateLimit(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "db1"}`)
+
+	reloaded := make(chan error, 1)
+	reloader, err := ReloadOnSIGHUP(FileSource(configPath), ReloadSettings{
+		MinInterval: time.Hour,
+		AfterReload: func(old, new Config, err error) {
+			reloaded <- err
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+	defer reloader.Stop()
+
+	writeConfigFile(t, configPath, `{"host": "db2"}`)
+	require.NoError(t, sendSIGHUP(t, reloaded), "The first reload must not be rate limited")
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+
+	writeConfigFile(t, configPath, `{"host": "db3"}`)
+	require.EqualError(t, sendSIGHUP(t, reloaded), ErrorReloadRateLimited.Error())
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+}
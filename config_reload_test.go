@@ -0,0 +1,200 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errorForTestReloadValidation = errors.New("Host must be specified")
+	errorForTestBeforeReload     = errors.New("Reload is canceled")
+)
+
+func validateReloadedConfig(config Config) error {
+	if _, err := config.GetString("/host"); err != nil {
+		return errorForTestReloadValidation
+	}
+	return nil
+}
+
+func getReloadedHost(t *testing.T, reloader *Reloader) string {
+	host, err := reloader.Config().GetString("/host")
+	require.NoError(t, err, "Cannot get value from config")
+	return host
+}
+
+// Tests.
+func TestReloaderReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "db1"}`)
+
+	var calls []string
+	reloader, err := NewReloader(FileSource(configPath), ReloadSettings{
+		Validator: validateReloadedConfig,
+		BeforeReload: func(current Config) error {
+			calls = append(calls, "before")
+			return nil
+		},
+		AfterReload: func(old, new Config, err error) {
+			calls = append(calls, "after")
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+	require.Equal(t, "db1", getReloadedHost(t, reloader))
+	require.Empty(t, calls, "Hooks must not be called on creation")
+
+	writeConfigFile(t, configPath, `{"host": "db2"}`)
+	require.NoError(t, reloader.Reload(), "Cannot reload config")
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+	require.Equal(t, []string{"before", "after"}, calls)
+}
+
+func TestReloaderKeepsConfigOnFailedReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, configPath, "host: db1\n")
+
+	var reloadErrors []error
+	reloader, err := NewReloader(FileSource(configPath), ReloadSettings{
+		Validator: validateReloadedConfig,
+		AfterReload: func(old, new Config, err error) {
+			reloadErrors = append(reloadErrors, err)
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+
+	writeConfigFile(t, configPath, "port: 5432\n")
+	require.EqualError(t, reloader.Reload(), errorForTestReloadValidation.Error())
+	require.Equal(t, "db1", getReloadedHost(t, reloader))
+
+	writeConfigFile(t, configPath, "host: [db2\n")
+	require.Error(t, reloader.Reload(), "Incorrect config reloaded successfully")
+	require.Equal(t, "db1", getReloadedHost(t, reloader))
+
+	require.Len(t, reloadErrors, 2)
+	require.EqualError(t, reloadErrors[0], errorForTestReloadValidation.Error())
+	require.Error(t, reloadErrors[1])
+}
+
+func TestReloaderBeforeReloadCancel(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.ini")
+	writeConfigFile(t, configPath, "host=db1")
+
+	reloader, err := NewReloader(FileSource(configPath), ReloadSettings{
+		BeforeReload: func(current Config) error {
+			return errorForTestBeforeReload
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+
+	writeConfigFile(t, configPath, "host=db2")
+	require.EqualError(t, reloader.Reload(), errorForTestBeforeReload.Error())
+	require.Equal(t, "db1", getReloadedHost(t, reloader))
+}
+
+func TestReloaderRateLimit(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "db1"}`)
+
+	reloader, err := NewReloader(FileSource(configPath), ReloadSettings{MinInterval: time.Hour,
+		Validator: func(config Config) error {
+			_, err := config.GetString("/host")
+			return err
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+
+	writeConfigFile(t, configPath, `{"hots": "db2"}`)
+	require.ErrorIs(t, reloader.Reload(), ErrorNotFound, "The first reload must not be rate limited")
+	require.Equal(t, "db1", getReloadedHost(t, reloader))
+
+	writeConfigFile(t, configPath, `{"host": "db2"}`)
+	require.NoError(t, reloader.Reload(), "Failed reload must not delay next reload")
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+
+	writeConfigFile(t, configPath, `{"host": "db3"}`)
+	require.EqualError(t, reloader.Reload(), ErrorReloadRateLimited.Error())
+	require.Equal(t, "db2", getReloadedHost(t, reloader))
+	reloader.Stop()
+}
+
+func TestReloaderRateLimitDelaysReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "db1"}`)
+
+	reloaded := make(chan error, 10)
+	reloader, err := NewReloader(FileSource(configPath), ReloadSettings{
+		MinInterval: 100 * time.Millisecond,
+		AfterReload: func(old, new Config, err error) {
+			reloaded <- err
+		}})
+	require.NoError(t, err, "Cannot create reloader")
+	defer reloader.Stop()
+
+	writeConfigFile(t, configPath, `{"host": "db2"}`)
+	require.NoError(t, reloader.Reload(), "Cannot reload config")
+	require.NoError(t, <-reloaded)
+
+	writeConfigFile(t, configPath, `{"host": "db3"}`)
+	require.ErrorIs(t, reloader.Reload(), ErrorReloadRateLimited)
+	writeConfigFile(t, configPath, `{"host": "db4"}`)
+	require.ErrorIs(t, reloader.Reload(), ErrorReloadRateLimited)
+	require.ErrorIs(t, <-reloaded, ErrorReloadRateLimited)
+	require.ErrorIs(t, <-reloaded, ErrorReloadRateLimited)
+
+	select {
+	case err = <-reloaded:
+		require.NoError(t, err, "Delayed reload must succeed")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Rate limited reload is not performed after interval")
+	}
+	require.Equal(t, "db4", getReloadedHost(t, reloader), "The last request must be applied")
+
+	select {
+	case <-reloaded:
+		require.FailNow(t, "Rate limited requests must be coalesced into single reload")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestReloaderNotifier(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"host": "db1", "port": 5432}`)
+
+	notifier := NewChangeNotifier()
+	var hostChanges, portChanges int
+	notifier.OnChange("/host", func(old, new Config) { hostChanges++ })
+	notifier.OnChange("/port", func(old, new Config) { portChanges++ })
+
+	reloader, err := NewReloader(FileSource(configPath), ReloadSettings{Notifier: notifier})
+	require.NoError(t, err, "Cannot create reloader")
+
+	writeConfigFile(t, configPath, `{"host": "db2", "port": 5432}`)
+	require.NoError(t, reloader.Reload(), "Cannot reload config")
+	require.Equal(t, 1, hostChanges)
+	require.Equal(t, 0, portChanges)
+}
+
+func TestReloaderInitialReadError(t *testing.T) {
+	_, err := NewReloader(FileSource("/incorrectConfigPath.json"), ReloadSettings{})
+	require.Error(t, err)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeConfigFile(t, configPath, `{"port": 5432}`)
+
+	_, err = NewReloader(FileSource(configPath), ReloadSettings{Validator: validateReloadedConfig})
+	require.EqualError(t, err, errorForTestReloadValidation.Error())
+}
@@ -18,6 +18,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -164,7 +169,7 @@ func TestLoadEmptyConfig(t *testing.T) {
 	initValue := 5
 	value := initValue
 	err = LoadValue(config, "/", &value)
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, initValue, value, "Value must be unchanged")
 
 	err = LoadValueIgnoringMissingFieldErrors(config, "/", &value)
@@ -275,7 +280,7 @@ func TestLoadValueToIncorrectVariable(t *testing.T) {
 }
 
 type StructWithIncorrectFieldType struct {
-	Value *int
+	Value chan int
 }
 
 func TestLoadValueWithIncorrectFieldType(t *testing.T) {
@@ -284,7 +289,7 @@ func TestLoadValueWithIncorrectFieldType(t *testing.T) {
 
 	var value StructWithIncorrectFieldType
 	err = LoadValue(config, "/", &value)
-	require.EqualError(t, err, ErrorUnsupportedTypeToLoadValue.Error())
+	require.ErrorIs(t, err, ErrorUnsupportedTypeToLoadValue)
 }
 
 type StructWithIncorrectSliceElementType struct {
@@ -297,7 +302,7 @@ func TestLoadValueWithIncorrectSliceElementType(t *testing.T) {
 
 	var value StructWithIncorrectSliceElementType
 	err = LoadValue(config, "/", &value)
-	require.EqualError(t, err, ErrorUnsupportedTypeToLoadValue.Error())
+	require.ErrorIs(t, err, ErrorUnsupportedTypeToLoadValue)
 }
 
 func TestLoadValueSliceWithError(t *testing.T) {
@@ -308,7 +313,7 @@ func TestLoadValueSliceWithError(t *testing.T) {
 	var value StructWithLoadableField
 	err = LoadValue(config, "/", &value)
 
-	require.EqualError(t, err, errorForTestLoadLoadableValue.Error())
+	require.ErrorIs(t, err, errorForTestLoadLoadableValue)
 	checkEqual(t, value.Value, LoadableStruct{IntElement: expectedIntValue,
 		FloatElement: expectedFloatValue})
 }
@@ -319,7 +324,7 @@ func TestLoadValueWithLoadableFieldIncorrectType(t *testing.T) {
 
 	var value StructWithLoadableField
 	err = LoadValue(config, "/", &value)
-	require.EqualError(t, err, errorForTestLoadLoadableValue.Error())
+	require.ErrorIs(t, err, errorForTestLoadLoadableValue)
 }
 
 func TestLoadValueWithLoadableFieldLoadError(t *testing.T) {
@@ -328,7 +333,7 @@ func TestLoadValueWithLoadableFieldLoadError(t *testing.T) {
 
 	var value StructWithLoadableField
 	err = LoadValue(config, "/", &value)
-	require.EqualError(t, err, errorForTestLoadLoadableValue.Error())
+	require.ErrorIs(t, err, errorForTestLoadLoadableValue)
 }
 
 // Test loading numeric types.
@@ -521,3 +526,711 @@ func TestLoadFloat64Values(t *testing.T) {
 	loadValidValues(t, &value, reflect.ValueOf(expectedFloatValues))
 	checkFloatValues(t, value.Values)
 }
+
+// Test generic getters.
+func TestGetNumericValues(t *testing.T) {
+	config, err := CreateConfigFromString(oneLevelJSONConfig, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	int32Value, err := Get[int32](config, "/intElement")
+	require.NoError(t, err, "Cannot get int32 value")
+	require.Equal(t, int32(expectedIntValue), int32Value)
+
+	uint64Value, err := Get[uint64](config, "/intElement")
+	require.NoError(t, err, "Cannot get uint64 value")
+	require.Equal(t, uint64(expectedIntValue), uint64Value)
+
+	float32Value, err := Get[float32](config, "/floatElement")
+	require.NoError(t, err, "Cannot get float32 value")
+	checkFloatValue(t, float64(float32Value))
+}
+
+func TestGetValuesOfDifferentTypes(t *testing.T) {
+	config, err := CreateConfigFromString(oneLevelJSONConfig, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	checkStringValue(t, MustGet[string](config, "/stringElement"))
+	checkBoolValue(t, MustGet[bool](config, "/boolElement"))
+	checkDurationValue(t, MustGet[time.Duration](config, "/durationElement"))
+	checkTimeValue(t, MustGet[time.Time](config, "/timeElement"))
+
+	checkStringValues(t, MustGet[[]string](config, "/stringElements"))
+	checkBoolValues(t, MustGet[[]bool](config, "/boolElements"))
+	checkDurationValues(t, MustGet[[]time.Duration](config, "/durationElements"))
+	checkTimeValues(t, MustGet[[]time.Time](config, "/timeElements"))
+	checkEqual(t, MustGet[[]int16](config, "/intElements"), []int16{123, 456, 789})
+}
+
+func TestGetLoadableValue(t *testing.T) {
+	config, err := CreateConfigFromString(`{"Value": "123456 1.23456"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	value, err := Get[LoadableStruct](config, "/Value")
+	require.NoError(t, err, "Cannot get loadable value")
+	checkEqual(t, value, LoadableStruct{IntElement: expectedIntValue, FloatElement: expectedFloatValue})
+}
+
+func TestGetOverflowedValue(t *testing.T) {
+	config, err := CreateConfigFromString(`{"Value": 300, "Negative": -1, "Float": 1e300}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	value, err := Get[int8](config, "/Value")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+	require.Equal(t, int8(0), value)
+
+	_, err = Get[uint8](config, "/Value")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	_, err = Get[uint64](config, "/Negative")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	_, err = Get[float32](config, "/Float")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	int16Value, err := Get[int16](config, "/Value")
+	require.NoError(t, err, "Cannot get int16 value")
+	require.Equal(t, int16(300), int16Value)
+}
+
+func TestGetOr(t *testing.T) {
+	config, err := CreateConfigFromString(`{"Value": 300, "String": "value"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	require.Equal(t, 300, GetOr(config, "/Value", 10))
+	require.Equal(t, 10, GetOr(config, "/Absent", 10))
+	require.Equal(t, time.Second, GetOr(config, "/Absent", time.Second))
+
+	require.NotPanics(t, func() {
+		require.Equal(t, int64(10), GetOr(config, "/String", int64(10)), "Incorrect value")
+		require.Equal(t, int8(10), GetOr(config, "/Value", int8(10)), "Overflowed value")
+	}, "GetOr must not panic on incorrect values")
+}
+
+func TestMustGetPanics(t *testing.T) {
+	config, err := CreateConfigFromString(`{}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	require.PanicsWithError(t, "Not found by path '/Value', expected type int",
+		func() { MustGet[int](config, "/Value") })
+}
+
+// Test overflow checks.
+func TestLoadOverflowedValue(t *testing.T) {
+	config, err := CreateConfigFromString(`{"Value": 300}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value struct{ Value int8 }
+	err = LoadValue(config, "/", &value)
+	require.ErrorIs(t, err, ErrorValueOverflow)
+	require.EqualError(t, err,
+		"1:2: Value overflows type by path '/Value', value '300', expected type int8")
+
+	var pathError *PathError
+	require.ErrorAs(t, err, &pathError)
+	require.Equal(t, &PathError{Path: "/Value", Value: "300", Type: "int8", Line: 1, Column: 2,
+		Err: ErrorValueOverflow}, pathError)
+}
+
+func TestLoadNegativeValueToUnsigned(t *testing.T) {
+	for configType, test := range map[string]struct{ data, position string }{
+		JSON: {`{"Value": -1}`, "1:2"}, YAML: {"Value: -1", "1:1"},
+		XML: {"<Value>-1</Value>", "1:1"}, INI: {"Value = -1", "1:1"}} {
+
+		config, err := CreateConfigFromString(test.data, configType)
+		require.NoError(t, err, "Cannot load config")
+
+		var value struct{ Value uint64 }
+		err = LoadValue(config, "/", &value)
+		require.ErrorIs(t, err, ErrorValueOverflow, configType)
+		require.EqualError(t, err, test.position+
+			": Value overflows type by path '/Value', value '-1', expected type uint64", configType)
+	}
+}
+
+func TestLoadMaxUnsignedValue(t *testing.T) {
+	for configType, data := range map[string]string{YAML: "Value: 18446744073709551615",
+		XML: "<Value>18446744073709551615</Value>", INI: "Value = 18446744073709551615"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load config")
+
+		var value struct{ Value uint64 }
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Equal(t, uint64(math.MaxUint64), value.Value)
+
+		var signedValue struct{ Value int64 }
+		err = LoadValue(config, "/", &signedValue)
+		require.ErrorIs(t, err, ErrorValueOverflow, configType)
+	}
+}
+
+func TestLoadOverflowedValues(t *testing.T) {
+	config, err := CreateConfigFromString(`{"Values": [1, 200, 300], "Negative": [1, -1]}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var int8Values struct{ Values []int8 }
+	err = LoadValue(config, "/", &int8Values)
+	require.EqualError(t, err,
+		"1:2: Value overflows type by path '/Values', value '200', expected type []int8")
+
+	var uint8Values struct{ Values []uint8 }
+	err = LoadValue(config, "/", &uint8Values)
+	require.EqualError(t, err,
+		"1:2: Value overflows type by path '/Values', value '300', expected type []uint8")
+
+	var uintValues struct{ Negative []uint }
+	err = LoadValue(config, "/", &uintValues)
+	require.EqualError(t, err,
+		"1:27: Value overflows type by path '/Negative', value '-1', expected type []uint")
+
+	var int16Values struct{ Values []int16 }
+	err = LoadValue(config, "/", &int16Values)
+	require.NoError(t, err, "Cannot load values")
+	require.Equal(t, []int16{1, 200, 300}, int16Values.Values)
+}
+
+// Test path errors.
+func TestPathErrorMessage(t *testing.T) {
+	err := &PathError{Path: "/server/port", Type: "int64", Value: "http", File: "config.json",
+		Err: ErrorIncorrectValueType}
+	require.EqualError(t, err,
+		"config.json: Incorrect value type by path '/server/port', value 'http', expected type int64")
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+
+	err = &PathError{Path: "/server/port", Err: ErrorNotFound}
+	require.EqualError(t, err, "Not found by path '/server/port'")
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestPathErrorsFromAllConfigTypes(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"server": {"port": "http"}}`, YAML: "server:\n  port: http\n",
+		XML: "<server>\n  <port>http</port></server>", INI: "[server]\n  port = http"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		_, err = config.GetInt("/server/port")
+		var pathError *PathError
+		require.ErrorAs(t, err, &pathError, configType)
+		require.Equal(t, &PathError{Path: "/server/port", Type: "int64", Value: "http",
+			Line: pathError.Line, Err: ErrorIncorrectValueType, Column: pathError.Column},
+			pathError, configType)
+		if configType == JSON {
+			require.Equal(t, []int{1, 13}, []int{pathError.Line, pathError.Column})
+		} else {
+			require.Equal(t, []int{2, 3}, []int{pathError.Line, pathError.Column}, configType)
+		}
+
+		_, err = config.GetString("/server/host")
+		require.ErrorIs(t, err, ErrorNotFound, configType)
+		require.ErrorAs(t, err, &pathError, configType)
+		require.Equal(t, "/server/host", pathError.Path, configType)
+	}
+}
+
+func TestPathErrorFromLoadValue(t *testing.T) {
+	type serverData struct {
+		Host string `config:"host"`
+		Port uint16 `config:"port"`
+	}
+	var value struct {
+		Server serverData `config:"server"`
+	}
+
+	config, err := CreateConfigFromString(`{"server": {"host": "localhost", "port": "http"}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	err = LoadValue(config, "/", &value)
+	require.Equal(t, &PathError{Path: "/server/port", Type: "uint16", Value: "http", Line: 1,
+		Column: 34, Err: ErrorIncorrectValueType}, err)
+	require.Equal(t, "localhost", value.Server.Host)
+}
+
+func TestPathErrorContainsFileName(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, configPath, "server:\n  port: http\n")
+
+	config, err := ReadConfig(configPath)
+	require.NoError(t, err, "Cannot read config")
+
+	_, err = config.GetInt("/server/port")
+	require.EqualError(t, err, configPath+
+		":2:3: Incorrect value type by path '/server/port', value 'http', expected type int64")
+
+	configPart, err := config.GetConfigPart("/server")
+	require.NoError(t, err, "Cannot get config part")
+
+	_, err = GetDuration(configPart, "/port")
+	require.EqualError(t, err, configPath+
+		":2:3: time: invalid duration \"http\" by path '/port', value 'http', expected type time.Duration")
+}
+
+// Test collecting of all errors.
+type collectErrorsServerData struct {
+	Host string `config:"host"`
+	Port uint16 `config:"port"`
+}
+
+type collectErrorsData struct {
+	Name    string                  `config:"name"`
+	Workers int8                    `config:"workers"`
+	Server  collectErrorsServerData `config:"server"`
+	Debug   bool                    `config:"debug"`
+}
+
+func TestLoadValueCollectAllErrors(t *testing.T) {
+	config, err := CreateConfigFromString(`{"name": "service", "workers": 300,
+		"server": {"host": "localhost", "port": "http"}, "debug": "yes"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.CollectAllErrors = true
+
+	var value collectErrorsData
+	err = TunedLoadValue(config, settings, "/", &value)
+
+	var loadErrors *LoadErrors
+	require.ErrorAs(t, err, &loadErrors)
+	require.Len(t, loadErrors.Errors, 3)
+	require.EqualError(t, err, "Cannot load 3 value(s):\n"+
+		"\t1:21: Value overflows type by path '/workers', value '300', expected type int8\n"+
+		"\t2:35: Incorrect value type by path '/server/port', value 'http', expected type uint16\n"+
+		"\t2:52: Incorrect value type by path '/debug', value 'yes', expected type bool")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+	require.True(t, loadErrors.Is(ErrorValueOverflow), "Errors must be matched without Unwrap")
+	require.False(t, loadErrors.Is(ErrorNotFound))
+	var pathError *PathError
+	require.True(t, loadErrors.As(&pathError), "Errors must be matched without Unwrap")
+	require.Equal(t, "/workers", pathError.Path)
+
+	require.Equal(t, "service", value.Name)
+	require.Equal(t, "localhost", value.Server.Host)
+}
+
+func TestLoadValueCollectAllErrorsWithMissingFields(t *testing.T) {
+	config, err := CreateConfigFromString(`{"workers": 300, "server": {}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(true)
+	settings.CollectAllErrors = true
+
+	var value collectErrorsData
+	err = TunedLoadValue(config, settings, "/", &value)
+	require.EqualError(t, err, "Cannot load 1 value(s):\n"+
+		"\t1:2: Value overflows type by path '/workers', value '300', expected type int8")
+	require.False(t, errors.Is(err, ErrorNotFound), "Missing fields must be ignored")
+
+	settings.IgnoreMissingFieldErrors = false
+	err = TunedLoadValue(config, settings, "/", &value)
+	var loadErrors *LoadErrors
+	require.ErrorAs(t, err, &loadErrors)
+	require.Len(t, loadErrors.Errors, 5)
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestLoadValueCollectAllErrorsWithoutErrors(t *testing.T) {
+	config, err := CreateConfigFromString(`{"name": "service", "workers": 3,
+		"server": {"host": "localhost", "port": 80}, "debug": true}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.CollectAllErrors = true
+
+	var value collectErrorsData
+	err = TunedLoadValue(config, settings, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, collectErrorsData{Name: "service", Workers: 3,
+		Server: collectErrorsServerData{Host: "localhost", Port: 80}, Debug: true}, value)
+}
+
+// Test tag options.
+type tagOptionsServerData struct {
+	Host    string        `config:"host,required"`
+	Port    uint16        `config:"port" default:"8080"`
+	Timeout time.Duration `config:"timeout" default:"5s"`
+	Tags    []string      `config:"tags" default:"web api"`
+}
+
+type tagOptionsData struct {
+	Name     string               `config:"name,omitempty" default:"service"`
+	Comment  string               `config:"comment,omitempty"`
+	Server   tagOptionsServerData `config:"server"`
+	Internal string               `config:"-"`
+	hidden   string
+}
+
+func TestLoadValueWithTagOptions(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"name": "", "comment": "", "server": {"host": "localhost"}, "Internal": "value"}`,
+		YAML: "name: ''\ncomment: ''\nserver:\n  host: localhost\nInternal: value\nhidden: value\n",
+		XML:  "<name/><server><host>localhost</host></server><Internal>value</Internal>"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		value := tagOptionsData{Comment: "unchanged", hidden: "unchanged"}
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Equal(t, tagOptionsData{Name: "service", Comment: "unchanged", hidden: "unchanged",
+			Server: tagOptionsServerData{Host: "localhost", Port: 8080, Timeout: 5 * time.Second,
+				Tags: []string{"web", "api"}}}, value, configType)
+	}
+}
+
+func TestLoadNestedStructWithOmitEmpty(t *testing.T) {
+	type dbData struct {
+		Host string `config:"host"`
+		Port int    `config:"port" default:"5432"`
+	}
+	var value struct {
+		DB      dbData `config:"db,omitempty"`
+		Replica dbData `config:"replica,omitempty" default:"{}"`
+	}
+	config, err := CreateConfigFromString("<db><host>db1</host></db><replica><host>db2</host></replica>", XML)
+	require.NoError(t, err, "Cannot load config")
+
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, dbData{Host: "db1", Port: 5432}, value.DB, "Element with children is not empty")
+	require.Equal(t, dbData{Host: "db2", Port: 5432}, value.Replica, "Element with children is not empty")
+}
+
+func TestLoadValueOverridesDefaults(t *testing.T) {
+	config, err := CreateConfigFromString(`{"name": "proxy",
+		"server": {"host": "localhost", "port": 80, "timeout": "1s", "tags": ["web"]}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value tagOptionsData
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, tagOptionsData{Name: "proxy", Server: tagOptionsServerData{Host: "localhost",
+		Port: 80, Timeout: time.Second, Tags: []string{"web"}}}, value)
+}
+
+func TestLoadValueWithMissingRequiredField(t *testing.T) {
+	config, err := CreateConfigFromString(`{"server": {"port": 80}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value tagOptionsData
+	err = LoadValueIgnoringMissingFieldErrors(config, "/", &value)
+	require.ErrorIs(t, err, ErrorRequiredValueNotFound)
+	require.EqualError(t, err,
+		"Required value not found by path '/server/host', expected type string")
+
+	config, err = CreateConfigFromString(`{"server": {"host": ""}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	err = LoadValueIgnoringMissingFieldErrors(config, "/", &value)
+	require.NoError(t, err, "Empty required value must be loaded")
+}
+
+func TestLoadValueWithIncorrectDefault(t *testing.T) {
+	var value struct {
+		Port int8 `config:"port" default:"http"`
+	}
+	config, err := CreateConfigFromString(`{}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	err = LoadValue(config, "/", &value)
+	require.Equal(t, &PathError{Path: "/port", Type: "int8", Value: "http",
+		Err: ErrorIncorrectValueType}, err)
+}
+
+// Test loading of encoding.TextUnmarshaler values.
+type textUnmarshalerLevel int
+
+func (l *textUnmarshalerLevel) UnmarshalText(data []byte) error {
+	switch string(data) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("Unknown level")
+	}
+	return nil
+}
+
+type textUnmarshalerData struct {
+	Address net.IP                 `config:"address"`
+	Network netip.Prefix           `config:"network"`
+	Count   big.Int                `config:"count"`
+	Level   textUnmarshalerLevel   `config:"level"`
+	Levels  []textUnmarshalerLevel `config:"levels"`
+}
+
+func TestLoadTextUnmarshalerValue(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"address": "10.0.0.1", "network": "10.0.0.0/8", "count": "123456789012345678901234567890",
+			"level": "info", "levels": ["debug", "info"]}`,
+		YAML: "address: 10.0.0.1\nnetwork: 10.0.0.0/8\ncount: '123456789012345678901234567890'\n" +
+			"level: info\nlevels: [debug, info]\n",
+		XML: "<address>10.0.0.1</address><network>10.0.0.0/8</network>" +
+			"<count>123456789012345678901234567890</count><level>info</level><levels>debug info</levels>",
+		INI: "address = 10.0.0.1\nnetwork = 10.0.0.0/8\ncount = 123456789012345678901234567890\n" +
+			"level = info\nlevels = debug info\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value textUnmarshalerData
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Equal(t, "10.0.0.1", value.Address.String(), configType)
+		require.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), value.Network, configType)
+		require.Equal(t, "123456789012345678901234567890", value.Count.String(), configType)
+		require.Equal(t, textUnmarshalerLevel(1), value.Level, configType)
+		require.Equal(t, []textUnmarshalerLevel{0, 1}, value.Levels, configType)
+	}
+}
+
+func TestLoadTextUnmarshalerValueError(t *testing.T) {
+	config, err := CreateConfigFromString(`{"level": "trace"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value struct {
+		Level textUnmarshalerLevel `config:"level"`
+	}
+	err = LoadValue(config, "/", &value)
+	require.EqualError(t, err,
+		"1:2: Unknown level by path '/level', value 'trace', expected type config.textUnmarshalerLevel")
+}
+
+// Test loading of values from config parts.
+type configLoadableEndpoint struct {
+	Host string
+	Port int64
+}
+
+func (e *configLoadableEndpoint) LoadFromConfig(c Config) (err error) {
+	if address, err := c.GetString("/"); err == nil {
+		e.Host = address
+		return nil
+	}
+	if e.Host, err = c.GetString("/host"); err != nil {
+		return err
+	}
+	e.Port, err = c.GetInt("/port")
+	return err
+}
+
+type configLoadableData struct {
+	Primary configLoadableEndpoint `config:"primary"`
+	Backup  configLoadableEndpoint `config:"backup"`
+}
+
+func TestLoadConfigLoadableValue(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"primary": {"host": "db1", "port": 5432}, "backup": "db2"}`,
+		YAML: "primary:\n  host: db1\n  port: 5432\nbackup: db2\n",
+		XML:  "<primary><host>db1</host><port>5432</port></primary><backup>db2</backup>"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value configLoadableData
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Equal(t, configLoadableData{Primary: configLoadableEndpoint{Host: "db1", Port: 5432},
+			Backup: configLoadableEndpoint{Host: "db2"}}, value, configType)
+	}
+}
+
+func TestLoadConfigLoadableValueError(t *testing.T) {
+	config, err := CreateConfigFromString(`{"primary": {"host": "db1", "port": "http"}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value configLoadableData
+	err = LoadValueIgnoringMissingFieldErrors(config, "/", &value)
+	require.EqualError(t, err, "1:29: Incorrect value type by path '/primary/port', value 'http', "+
+		"expected type int64")
+}
+
+func TestLoadValueWithConfigLoader(t *testing.T) {
+	config, err := CreateConfigFromString("[limits]\nmin = 1\nmax = 10\n", INI)
+	require.NoError(t, err, "Cannot load config")
+
+	type limits struct{ Min, Max int64 }
+	settings := GetDefaultLoadSettings(false)
+	settings.ConfigLoaders = map[string]ConfigValueLoader{
+		reflect.TypeOf(limits{}).String(): func(c Config) (reflect.Value, error) {
+			var result limits
+			values, err := c.GetInts("/min", " ")
+			if err == nil && len(values) == 1 {
+				result.Min = values[0]
+				result.Max, err = c.GetInt("/max")
+			}
+			return reflect.ValueOf(result), err
+		}}
+
+	var value struct {
+		Limits limits `config:"limits"`
+	}
+	err = TunedLoadValue(config, settings, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, limits{Min: 1, Max: 10}, value.Limits)
+}
+
+// Test loading of pointers, arrays, interfaces and embedded structures.
+type pointerServerData struct {
+	Host string `config:"host"`
+}
+
+type pointerData struct {
+	Name    *string            `config:"name"`
+	Port    *int               `config:"port"`
+	Server  *pointerServerData `config:"server"`
+	Backup  *pointerServerData `config:"backup"`
+	Timeout *time.Duration     `config:"timeout" default:"5s"`
+}
+
+func TestLoadPointerValues(t *testing.T) {
+	config, err := CreateConfigFromString(`{"name": "service", "server": {"host": "localhost"}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value pointerData
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.NotNil(t, value.Name)
+	require.Equal(t, "service", *value.Name)
+	require.Nil(t, value.Port, "Absent value must not be allocated")
+	require.Equal(t, &pointerServerData{Host: "localhost"}, value.Server)
+	require.Nil(t, value.Backup, "Absent value must not be allocated")
+	require.NotNil(t, value.Timeout)
+	require.Equal(t, 5*time.Second, *value.Timeout)
+
+	var port *uint16
+	err = LoadValue(config, "/name", &port)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+	require.Nil(t, port)
+}
+
+func TestLoadNullPointerValues(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"name": null, "port": null, "server": null, "timeout": null}`,
+		YAML: "name: ~\nport:\nserver: null\ntimeout: ~\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value pointerData
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Nil(t, value.Name, configType)
+		require.Nil(t, value.Port, configType)
+		require.Nil(t, value.Server, configType)
+		require.Equal(t, 5*time.Second, *value.Timeout, "Default must be used for null (%s)", configType)
+	}
+}
+
+func TestLoadPointerValuesCollectAllErrors(t *testing.T) {
+	config, err := CreateConfigFromString(`{"server": {"host": "localhost", "port": "http"}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.CollectAllErrors = true
+
+	var value struct {
+		Server *collectErrorsServerData `config:"server"`
+	}
+	err = TunedLoadValue(config, settings, "/", &value)
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+	require.NotNil(t, value.Server, "Partially loaded value must be kept")
+	require.Equal(t, "localhost", value.Server.Host)
+}
+
+func TestLoadArrayValues(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"point": [1, 2, 3], "names": ["a", "b"]}`,
+		YAML: "point: [1, 2, 3]\nnames: [a, b]\n",
+		XML:  "<point>1 2 3</point><names>a b</names>",
+		INI:  "point = 1 2 3\nnames = a b\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value struct {
+			Point [3]int8 `config:"point"`
+		}
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Equal(t, [3]int8{1, 2, 3}, value.Point, configType)
+
+		var names [3]string
+		err = LoadValue(config, "/names", &names)
+		require.ErrorIs(t, err, ErrorArrayLengthMismatch, configType)
+	}
+}
+
+func TestLoadInterfaceValues(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"name": "service", "server": {"host": "localhost", "ports": ["80", "443"]}}`,
+		YAML: "name: service\nserver:\n  host: localhost\n  ports: ['80', '443']\n",
+		XML:  "<name>service</name><server><host>localhost</host><ports>80</ports><ports>443</ports></server>"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		var value struct {
+			Name   interface{} `config:"name"`
+			Server interface{} `config:"server"`
+		}
+		err = LoadValue(config, "/", &value)
+		require.NoError(t, err, "Cannot load value from %s-config", configType)
+		require.Equal(t, "service", value.Name, configType)
+		require.Equal(t, map[string]interface{}{"host": "localhost",
+			"ports": []interface{}{"80", "443"}}, value.Server, configType)
+	}
+
+	config, err := CreateConfigFromString("[server]\nhost = localhost\n[client]\nretries = 3\n", INI)
+	require.NoError(t, err, "Cannot load ini-config")
+
+	var value interface{}
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value from ini-config")
+	require.Equal(t, map[string]interface{}{"server": map[string]interface{}{"host": "localhost"},
+		"client": map[string]interface{}{"retries": "3"}}, value)
+}
+
+type embeddedCommonData struct {
+	Name string `config:"name"`
+}
+
+type embeddedNetworkData struct {
+	Host string `config:"host"`
+}
+
+type EmbeddedTimeoutData struct {
+	Timeout time.Duration `config:"timeout"`
+}
+
+type embeddedData struct {
+	embeddedCommonData
+	*embeddedNetworkData
+	EmbeddedTimeoutData `config:"limits"`
+	Port                int `config:"port" validate:"min=1"`
+}
+
+func TestLoadEmbeddedStructures(t *testing.T) {
+	config, err := CreateConfigFromString(`{"name": "service", "host": "localhost", "port": 80,
+		"limits": {"timeout": "1s"}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value embeddedData
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, "service", value.Name)
+	require.Nil(t, value.embeddedNetworkData, "Unexported pointer must not be allocated")
+	require.Equal(t, time.Second, value.Timeout)
+	require.Equal(t, 80, value.Port)
+
+	var exported struct {
+		*EmbeddedTimeoutData
+	}
+	err = LoadValue(config, "/limits", &exported)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, &EmbeddedTimeoutData{Timeout: time.Second}, exported.EmbeddedTimeoutData)
+}
@@ -0,0 +1,128 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts that may be used in 'TimeSettings'.
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// TimeSettings is settings that used to load 'time.Time' values.
+type TimeSettings struct {
+	// Layouts that are tried in order to parse time (RFC3339 is used if it is empty).
+	Layouts []string
+	// Location of time values without time zone (UTC is used if it is nil).
+	Location *time.Location
+	// Unit of numeric Unix timestamps (e.g. 'time.Second' or 'time.Millisecond'), numeric
+	// values are parsed by layouts only if it is zero.
+	TimestampUnit time.Duration
+}
+
+// GetDefaultTimeSettings returns settings that accept RFC3339 time, RFC1123 time, date and
+// date with time in layout '2006-01-02 15:04:05' and Unix timestamps in seconds.
+func GetDefaultTimeSettings() *TimeSettings {
+	return &TimeSettings{
+		Layouts:       []string{time.RFC3339Nano, time.RFC1123Z, time.RFC1123, DateTimeLayout, DateLayout},
+		TimestampUnit: time.Second}
+}
+
+// Parse parses time by layouts or as Unix timestamp. Error of the first layout is returned if
+// time cannot be parsed.
+func (s *TimeSettings) Parse(data string) (time.Time, error) {
+	layouts, location := s.Layouts, s.Location
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	var firstErr error
+	for _, layout := range layouts {
+		value, err := time.ParseInLocation(layout, data, location)
+		if err == nil {
+			return value, nil
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if s.TimestampUnit > 0 {
+		if value, err := parseTimestamp(data, s.TimestampUnit); err == nil {
+			return value.In(location), nil
+		}
+	}
+	return time.Time{}, firstErr
+}
+
+// parseTimestamp parses Unix timestamp in specified units. Timestamp may be fractional
+// or in exponential form (numbers of JSON configs).
+func parseTimestamp(data string, unit time.Duration) (time.Time, error) {
+	if value, err := strconv.ParseInt(data, 10, 64); err == nil {
+		if unit >= time.Second {
+			return time.Unix(value*int64(unit/time.Second), 0), nil
+		}
+		perSecond := int64(time.Second / unit)
+		return time.Unix(value/perSecond, value%perSecond*int64(unit)), nil
+	}
+	value, err := strconv.ParseFloat(data, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return time.Time{}, fmt.Errorf("%w: timestamp %q", ErrorIncorrectValueFormat, data)
+	}
+	seconds, fraction := math.Modf(value * unit.Seconds())
+	return time.Unix(int64(seconds), int64(math.Round(fraction*float64(time.Second)))), nil
+}
+
+// ParseWeekday parses day of week by its full ('Monday') or short ('Mon') name in any case
+// or by number from 0 (Sunday) to 6.
+func ParseWeekday(data string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(data))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if dayName := strings.ToLower(day.String()); name == dayName || name == dayName[:3] {
+			return day, nil
+		}
+	}
+	if number, err := strconv.Atoi(name); err == nil && number >= 0 && number <= 6 {
+		return time.Weekday(number), nil
+	}
+	return 0, fmt.Errorf("%w: weekday %q", ErrorIncorrectValueFormat, data)
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// getTimeLoader returns loader of 'time.Time' values that uses time settings.
+func getTimeLoader(settings LoadSettings, valueType reflect.Type) valueLoader {
+	if settings.Time == nil || valueType != timeType {
+		return nil
+	}
+	return func(data string, value reflect.Value) (reflect.Value, error) {
+		loadedValue, err := settings.Time.Parse(data)
+		if err == nil {
+			value.Set(reflect.ValueOf(loadedValue))
+		}
+		return value, err
+	}
+}
@@ -0,0 +1,137 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type scheduleData struct {
+	Start    time.Time      `config:"start"`
+	End      time.Time      `config:"end"`
+	Created  time.Time      `config:"created"`
+	Updated  time.Time      `config:"updated"`
+	Holidays []time.Time    `config:"holidays"`
+	Zone     *time.Location `config:"zone"`
+	Day      time.Weekday   `config:"day"`
+}
+
+// Tests.
+func TestTimeSettingsParse(t *testing.T) {
+	location := time.FixedZone("UTC+3", 3*60*60)
+	settings := GetDefaultTimeSettings()
+	settings.Location = location
+
+	for data, expected := range map[string]time.Time{
+		"2024-03-01T10:20:30Z":            time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC),
+		"2024-03-01T10:20:30.5+01:00":     time.Date(2024, 3, 1, 9, 20, 30, 5e8, time.UTC),
+		"Fri, 01 Mar 2024 10:20:30 +0000": time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC),
+		"2024-03-01 10:20:30":             time.Date(2024, 3, 1, 10, 20, 30, 0, location),
+		"2024-03-01":                      time.Date(2024, 3, 1, 0, 0, 0, 0, location),
+		"1709288430":                      time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC),
+		"1.70928843e+09":                  time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC),
+	} {
+		value, err := settings.Parse(data)
+		require.NoError(t, err, data)
+		require.True(t, expected.Equal(value), "%s: expected %v, actual %v", data, expected, value)
+	}
+
+	_, err := settings.Parse("01.03.2024")
+	require.Error(t, err)
+
+	settings = &TimeSettings{Layouts: []string{"02.01.2006"}, TimestampUnit: time.Millisecond}
+	value, err := settings.Parse("01.03.2024")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), value)
+
+	value, err = settings.Parse("1709288430250")
+	require.NoError(t, err)
+	require.True(t, time.Date(2024, 3, 1, 10, 20, 30, 25e7, time.UTC).Equal(value))
+	require.Equal(t, time.UTC, value.Location())
+
+	_, err = settings.Parse("2024-03-01T10:20:30Z")
+	require.Error(t, err)
+}
+
+func TestParseWeekday(t *testing.T) {
+	for data, expected := range map[string]time.Weekday{
+		"Monday": time.Monday,
+		"sun":    time.Sunday,
+		"FRI":    time.Friday,
+		"6":      time.Saturday,
+	} {
+		value, err := ParseWeekday(data)
+		require.NoError(t, err, data)
+		require.Equal(t, expected, value, data)
+	}
+
+	for _, data := range []string{"", "7", "Mo", "Funday"} {
+		_, err := ParseWeekday(data)
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, data)
+	}
+}
+
+func TestLoadTimeValuesWithSettings(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"start": "2024-03-01", "end": "2024-03-02 18:00:00", "created": 1709288430,
+			"updated": "Fri, 01 Mar 2024 10:20:30 GMT", "holidays": ["2024-03-08", 1709856000],
+			"zone": "Europe/Moscow", "day": "Mon"}`,
+		YAML: "start: '2024-03-01'\nend: '2024-03-02 18:00:00'\ncreated: 1709288430\n" +
+			"updated: Fri, 01 Mar 2024 10:20:30 GMT\nholidays: ['2024-03-08', 1709856000]\n" +
+			"zone: Europe/Moscow\nday: 1\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		settings := GetDefaultLoadSettings(false)
+		settings.Time = GetDefaultTimeSettings()
+		settings.Time.Location, err = time.LoadLocation("Europe/Moscow")
+		require.NoError(t, err, "Cannot load location")
+
+		var value scheduleData
+		require.NoError(t, TunedLoadValue(config, settings, "/", &value), configType)
+		require.Equal(t, "2024-02-29T21:00:00Z", value.Start.UTC().Format(time.RFC3339), configType)
+		require.Equal(t, "2024-03-02T15:00:00Z", value.End.UTC().Format(time.RFC3339), configType)
+		require.Equal(t, "2024-03-01T10:20:30Z", value.Created.UTC().Format(time.RFC3339), configType)
+		require.Equal(t, "2024-03-01T10:20:30Z", value.Updated.UTC().Format(time.RFC3339), configType)
+		require.Len(t, value.Holidays, 2, configType)
+		require.Equal(t, "2024-03-07T21:00:00Z", value.Holidays[0].UTC().Format(time.RFC3339), configType)
+		require.Equal(t, "2024-03-08T00:00:00Z", value.Holidays[1].UTC().Format(time.RFC3339), configType)
+		require.Equal(t, "Europe/Moscow", value.Zone.String(), configType)
+		require.Equal(t, time.Monday, value.Day, configType)
+
+		var rfc3339Only scheduleData
+		err = LoadValue(config, "/", &rfc3339Only)
+		require.Error(t, err, "Time must be parsed only as RFC3339 by default")
+	}
+}
+
+func TestLoadIncorrectLocation(t *testing.T) {
+	config, err := CreateConfigFromString(`{"zone": "Mars/Olympus"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value struct {
+		Zone *time.Location `config:"zone"`
+	}
+	err = LoadValue(config, "/", &value)
+	var pathError *PathError
+	require.ErrorAs(t, err, &pathError)
+	require.Equal(t, "/zone", pathError.Path)
+	require.Equal(t, "*time.Location", pathError.Type)
+	require.Nil(t, value.Zone)
+}
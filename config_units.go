@@ -0,0 +1,200 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is size in bytes. It is loaded from number with optional SI (kB, MB, GB, TB, PB,
+// EB) or IEC (KiB, MiB, GiB, TiB, PiB, EiB) suffix, e.g. '512', '10MiB' or '1.5 GB'.
+// Suffixes are case insensitive, 'K', 'M', 'G' and etc are treated as SI suffixes.
+type ByteSize uint64
+
+// Byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+	EB ByteSize = 1000 * PB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+	EiB ByteSize = 1024 * PiB
+)
+
+var (
+	byteSizeUnits = map[string]ByteSize{"": Byte, "b": Byte,
+		"k": KB, "kb": KB, "m": MB, "mb": MB, "g": GB, "gb": GB,
+		"t": TB, "tb": TB, "p": PB, "pb": PB, "e": EB, "eb": EB,
+		"ki": KiB, "kib": KiB, "mi": MiB, "mib": MiB, "gi": GiB, "gib": GiB,
+		"ti": TiB, "tib": TiB, "pi": PiB, "pib": PiB, "ei": EiB, "eib": EiB}
+	byteSizeNames = []struct {
+		unit ByteSize
+		name string
+	}{{EiB, "EiB"}, {PiB, "PiB"}, {TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"},
+		{EB, "EB"}, {PB, "PB"}, {TB, "TB"}, {GB, "GB"}, {MB, "MB"}, {KB, "kB"}}
+)
+
+// ParseByteSize parses byte size.
+func ParseByteSize(data string) (ByteSize, error) {
+	number, unit := splitNumberAndUnit(strings.TrimSpace(data))
+	multiplier, exist := byteSizeUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !exist || len(number) == 0 {
+		return 0, fmt.Errorf("%w: byte size %q", ErrorIncorrectValueFormat, data)
+	}
+	if value, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if value > math.MaxUint64/uint64(multiplier) {
+			return 0, ErrorValueOverflow
+		}
+		return ByteSize(value) * multiplier, nil
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%w: byte size %q", ErrorIncorrectValueFormat, data)
+	}
+	if value = math.Round(value * float64(multiplier)); value >= math.MaxUint64 {
+		return 0, ErrorValueOverflow
+	}
+	return ByteSize(value), nil
+}
+
+// LoadValueFromConfig loads byte size from config (see 'Loadable').
+func (s *ByteSize) LoadValueFromConfig(data string) (err error) {
+	*s, err = ParseByteSize(data)
+	return err
+}
+
+// String returns byte size with the largest unit that divides it without remainder.
+func (s ByteSize) String() string {
+	for _, unit := range byteSizeNames {
+		if s != 0 && s%unit.unit == 0 {
+			return strconv.FormatUint(uint64(s/unit.unit), 10) + unit.name
+		}
+	}
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
+
+// Percent is ratio loaded from percentage ('75%') or from plain number ('0.75'). Value of
+// both examples is 0.75.
+type Percent float64
+
+// ParsePercent parses percentage or ratio.
+func ParsePercent(data string) (Percent, error) {
+	number := strings.TrimSpace(data)
+	divider := 1.0
+	if strings.HasSuffix(number, "%") {
+		number, divider = strings.TrimSpace(strings.TrimSuffix(number, "%")), 100
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%w: percent %q", ErrorIncorrectValueFormat, data)
+	}
+	return Percent(value / divider), nil
+}
+
+// LoadValueFromConfig loads ratio from config (see 'Loadable').
+func (p *Percent) LoadValueFromConfig(data string) (err error) {
+	*p, err = ParsePercent(data)
+	return err
+}
+
+// String returns percentage representation of ratio.
+func (p Percent) String() string {
+	return strconv.FormatFloat(float64(p)*100, 'f', -1, 64) + "%"
+}
+
+// ParseDuration parses duration in the same way as 'time.ParseDuration' but additionally
+// supports units 'd' (24 hours) and 'w' (7 days), e.g. '7d' or '1w2d12h'.
+func ParseDuration(data string) (time.Duration, error) {
+	if !strings.ContainsAny(data, "dw") {
+		return time.ParseDuration(data)
+	}
+	rest, sign := data, time.Duration(1)
+	if len(rest) > 0 && (rest[0] == '-' || rest[0] == '+') {
+		if rest[0] == '-' {
+			sign = -1
+		}
+		rest = rest[1:]
+	}
+
+	var result time.Duration
+	var standardPart strings.Builder
+	for len(rest) > 0 {
+		number, unit := splitNumberAndUnit(rest)
+		unitLength := strings.IndexFunc(unit, isDigitOrPoint)
+		if unitLength < 0 {
+			unitLength = len(unit)
+		}
+		unit, rest = unit[:unitLength], unit[unitLength:]
+		if len(number) == 0 {
+			return 0, fmt.Errorf("%w: duration %q", ErrorIncorrectValueFormat, data)
+		}
+
+		var multiplier time.Duration
+		switch unit {
+		case "d":
+			multiplier = 24
+		case "w":
+			multiplier = 7 * 24
+		default:
+			standardPart.WriteString(number + unit)
+			continue
+		}
+		hours, err := time.ParseDuration(number + "h")
+		if err != nil {
+			return 0, fmt.Errorf("%w: duration %q", ErrorIncorrectValueFormat, data)
+		}
+		if hours > math.MaxInt64/multiplier || result > math.MaxInt64-hours*multiplier {
+			return 0, ErrorValueOverflow
+		}
+		result += hours * multiplier
+	}
+	if standardPart.Len() > 0 {
+		duration, err := time.ParseDuration(standardPart.String())
+		if err != nil {
+			return 0, fmt.Errorf("%w: duration %q", ErrorIncorrectValueFormat, data)
+		}
+		if result > math.MaxInt64-duration {
+			return 0, ErrorValueOverflow
+		}
+		result += duration
+	}
+	return sign * result, nil
+}
+
+// splitNumberAndUnit splits data into leading number and the rest.
+func splitNumberAndUnit(data string) (number string, unit string) {
+	numberLength := strings.IndexFunc(data, func(symbol rune) bool { return !isDigitOrPoint(symbol) })
+	if numberLength < 0 {
+		return data, ""
+	}
+	return data[:numberLength], data[numberLength:]
+}
+
+func isDigitOrPoint(symbol rune) bool {
+	return ('0' <= symbol && symbol <= '9') || symbol == '.'
+}
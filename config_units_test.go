@@ -0,0 +1,173 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type unitsData struct {
+	MaxBody   ByteSize      `config:"max_body" validate:"max=1GiB"`
+	Ratio     Percent       `config:"ratio"`
+	Retention time.Duration `config:"retention" validate:"min=1d"`
+	Buffers   []ByteSize    `config:"buffers"`
+}
+
+// Tests.
+func TestParseByteSize(t *testing.T) {
+	for data, expected := range map[string]ByteSize{
+		"512":     512,
+		"10MiB":   10 * MiB,
+		"10mib":   10 * MiB,
+		"1.5 GB":  1500 * MB,
+		"2k":      2 * KB,
+		"0.5KiB":  512,
+		"64B":     64,
+		" 1 TiB ": TiB,
+	} {
+		value, err := ParseByteSize(data)
+		require.NoError(t, err, data)
+		require.Equal(t, expected, value, data)
+	}
+
+	for _, data := range []string{"", "MiB", "10 XB", "-1KB", "1..5MB"} {
+		_, err := ParseByteSize(data)
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, data)
+	}
+	_, err := ParseByteSize("16EiB")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	require.Equal(t, "10MiB", (10 * MiB).String())
+	require.Equal(t, "1500MB", (1500 * MB).String())
+	require.Equal(t, "1kB", (1000 * Byte).String())
+	require.Equal(t, "1023B", ByteSize(1023).String())
+}
+
+func TestParsePercent(t *testing.T) {
+	for data, expected := range map[string]Percent{
+		"75%":   0.75,
+		"12.5%": 0.125,
+		"0.75":  0.75,
+		"150 %": 1.5,
+	} {
+		value, err := ParsePercent(data)
+		require.NoError(t, err, data)
+		require.InDelta(t, float64(expected), float64(value), 1e-9, data)
+	}
+
+	for _, data := range []string{"", "%", "75%%", "NaN"} {
+		_, err := ParsePercent(data)
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, data)
+	}
+
+	require.Equal(t, "75%", Percent(0.75).String())
+}
+
+func TestParseDuration(t *testing.T) {
+	for data, expected := range map[string]time.Duration{
+		"90s":       90 * time.Second,
+		"7d":        7 * 24 * time.Hour,
+		"1w2d12h":   (9*24 + 12) * time.Hour,
+		"1.5d":      36 * time.Hour,
+		"-1d30m":    -(24*time.Hour + 30*time.Minute),
+		"2h1d500ms": 26*time.Hour + 500*time.Millisecond,
+	} {
+		value, err := ParseDuration(data)
+		require.NoError(t, err, data)
+		require.Equal(t, expected, value, data)
+	}
+
+	for _, data := range []string{"d", "1dd", "1d1", "1d1x"} {
+		_, err := ParseDuration(data)
+		require.ErrorIs(t, err, ErrorIncorrectValueFormat, data)
+	}
+	_, err := ParseDuration("20000w")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+}
+
+func TestGetUnitValues(t *testing.T) {
+	for configType, data := range map[string]string{
+		JSON: `{"max_body": "10MiB", "ratio": "75%", "retention": "7d", "buffers": ["4KiB", "1MB"]}`,
+		YAML: "max_body: 10MiB\nratio: 75%\nretention: 7d\nbuffers: [4KiB, 1MB]\n",
+		XML:  "<max_body>10MiB</max_body><ratio>75%</ratio><retention>7d</retention><buffers>4KiB 1MB</buffers>",
+		INI:  "max_body = 10MiB\nratio = 75%\nretention = 7d\nbuffers = 4KiB 1MB\n"} {
+
+		config, err := CreateConfigFromString(data, configType)
+		require.NoError(t, err, "Cannot load %s-config", configType)
+
+		size, err := GetByteSize(config, "/max_body")
+		require.NoError(t, err, configType)
+		require.Equal(t, 10*MiB, size, configType)
+
+		sizes, err := GetByteSizes(config, "/buffers", defaultArrayDelimiter)
+		require.NoError(t, err, configType)
+		require.Equal(t, []ByteSize{4 * KiB, MB}, sizes, configType)
+
+		ratio, err := GetPercent(config, "/ratio")
+		require.NoError(t, err, configType)
+		require.Equal(t, Percent(0.75), ratio, configType)
+
+		retention, err := GetDuration(config, "/retention")
+		require.NoError(t, err, configType)
+		require.Equal(t, 7*24*time.Hour, retention, configType)
+
+		var value unitsData
+		require.NoError(t, LoadValue(config, "/", &value), configType)
+		require.Equal(t, unitsData{MaxBody: 10 * MiB, Ratio: 0.75, Retention: 7 * 24 * time.Hour,
+			Buffers: []ByteSize{4 * KiB, MB}}, value, configType)
+	}
+}
+
+func TestLoadUnitValuesWithCustomLoaders(t *testing.T) {
+	config, err := CreateConfigFromString(`{"max_body": "10MiB", "ratio": "75%", "buffers": ["4KiB", "1MB"]}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.Loaders = map[string]StringValueLoader{}
+	var value struct {
+		MaxBody ByteSize   `config:"max_body"`
+		Ratio   Percent    `config:"ratio"`
+		Buffers []ByteSize `config:"buffers"`
+	}
+	require.NoError(t, TunedLoadValue(config, settings, "/", &value))
+	require.Equal(t, 10*MiB, value.MaxBody)
+	require.Equal(t, Percent(0.75), value.Ratio)
+	require.Equal(t, []ByteSize{4 * KiB, MB}, value.Buffers)
+}
+
+func TestLoadIncorrectUnitValues(t *testing.T) {
+	config, err := CreateConfigFromString(`{"max_body": "2GiB", "ratio": "high", "retention": "12h"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	_, err = GetPercent(config, "/ratio")
+	require.ErrorIs(t, err, ErrorIncorrectValueFormat)
+	require.EqualError(t, err, "1:22: Incorrect value format: percent \"high\" by path '/ratio', "+
+		"value 'high', expected type config.Percent")
+
+	var size struct {
+		MaxBody ByteSize `config:"max_body" validate:"max=1GiB"`
+	}
+	err = LoadValue(config, "/", &size)
+	require.ErrorIs(t, err, ErrorValidationFailed)
+
+	var retention struct {
+		Retention time.Duration `config:"retention" validate:"min=1d"`
+	}
+	err = LoadValue(config, "/", &retention)
+	require.ErrorIs(t, err, ErrorValidationFailed)
+}
@@ -0,0 +1,401 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ValidateTagKey tag name of validation rules of structure fields.
+	validateTagKey = "validate"
+)
+
+// Errors returned from validation.
+var (
+	ErrorValidationFailed        = errors.New("Value does not satisfy rule")
+	ErrorIncorrectValidationRule = errors.New("Incorrect validation rule")
+
+	errorUnknownValidationRule = errors.New("Unknown rule")
+)
+
+var (
+	validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+)
+
+// Validator is interface that may be implemented by loaded values to check them. Method
+// 'Validate' is called after value and all its fields are loaded and validated.
+//
+// Fields of structures are also validated by rules specified in tag 'validate' separated
+// by comma:
+//   - 'min=N', 'max=N' check number or length of string, slice or map;
+//   - 'len=N' checks length of string, slice or map;
+//   - 'oneof=a b c' checks that value is one of space separated values;
+//   - 'nonzero' checks that value is not zero value of its type;
+//   - 'regexp=pattern' checks that string matches pattern, it must be the last rule;
+//   - 'gtfield=F', 'gtefield=F', 'ltfield=F', 'ltefield=F' compare value with field 'F' of
+//     the same structure.
+//
+// Length of string is counted in runes, limits of 'time.Duration' and 'ByteSize' values may
+// be specified as durations and byte sizes. Rules of pointer fields are applied to values they
+// point to, nil pointers are checked only by 'nonzero'. Elements of slices, arrays and maps
+// are validated too.
+type Validator interface {
+	Validate() error
+}
+
+// validateValue checks loaded value by rules in tags of its fields and by 'Validator' interface.
+func validateValue(c Config, settings LoadSettings, path string, value reflect.Value) error {
+	var errs []error
+	addError := func(err error) bool {
+		if loadErrors, ok := err.(*LoadErrors); ok {
+			errs = append(errs, loadErrors.Errors...)
+		} else if err != nil {
+			errs = append(errs, err)
+		}
+		return len(errs) == 0 || settings.CollectAllErrors
+	}
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if isLoadedByFields(c, settings, value.Type()) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			options := getFieldOptions(field)
+			fieldPath := joinPath(path, options.name)
+			if options.embedded && isLoadedByFields(c, settings, indirectType(field.Type)) {
+				fieldPath = path
+			} else if options.skip {
+				continue
+			}
+			err := validateField(c, fieldPath, value, i, field.Tag.Get(validateTagKey))
+			if !addError(err) {
+				break
+			}
+			if !addError(validateValue(c, settings, fieldPath, value.Field(i))) {
+				break
+			}
+		}
+	}
+	if len(errs) == 0 || settings.CollectAllErrors {
+		addError(validateElements(c, settings, path, value))
+	}
+	if len(errs) == 0 {
+		addError(callValidator(c, path, value))
+	}
+
+	switch {
+	case len(errs) == 0:
+		return nil
+	case settings.CollectAllErrors:
+		return &LoadErrors{Errors: errs}
+	}
+	return errs[0]
+}
+
+// validateElements validates elements of slices, arrays and maps. Elements are addressed by
+// indexes and keys in paths.
+func validateElements(c Config, settings LoadSettings, path string, value reflect.Value) error {
+	var elements []reflect.Value
+	var paths []string
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !mayBeValidated(value.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			elements = append(elements, value.Index(i))
+			paths = append(paths, joinPath(path, strconv.Itoa(i)))
+		}
+	case reflect.Map:
+		if !mayBeValidated(value.Type().Elem()) {
+			return nil
+		}
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			// Elements of maps are copied, so methods with pointer receivers may be called.
+			element := reflect.New(value.Type().Elem()).Elem()
+			element.Set(value.MapIndex(key))
+			elements = append(elements, element)
+			paths = append(paths, joinPath(path, fmt.Sprint(key.Interface())))
+		}
+	}
+
+	var errs []error
+	for i, element := range elements {
+		err := validateValue(c, settings, paths[i], element)
+		if loadErrors, ok := err.(*LoadErrors); ok {
+			errs = append(errs, loadErrors.Errors...)
+		} else if err != nil {
+			errs = append(errs, err)
+		}
+		if err != nil && !settings.CollectAllErrors {
+			return err
+		}
+	}
+	if len(errs) != 0 {
+		return &LoadErrors{Errors: errs}
+	}
+	return nil
+}
+
+// mayBeValidated checks whether values of type may have validation rules or 'Validator'.
+func mayBeValidated(valueType reflect.Type) bool {
+	switch indirectType(valueType).Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return valueType.Implements(validatorType) || reflect.PtrTo(valueType).Implements(validatorType)
+}
+
+func callValidator(c Config, path string, value reflect.Value) error {
+	var validator Validator
+	if !value.CanInterface() {
+		// Unexported embedded structures are validated only by their fields.
+		return nil
+	} else if value.CanAddr() {
+		validator, _ = value.Addr().Interface().(Validator)
+	} else {
+		validator, _ = value.Interface().(Validator)
+	}
+	if validator == nil {
+		return nil
+	}
+	return newValidationError(c, path, value, validator.Validate())
+}
+
+func validateField(c Config, path string, structValue reflect.Value, i int, tag string) error {
+	value := structValue.Field(i)
+	for _, rule := range splitValidationRules(tag) {
+		name, param := rule, ""
+		if separator := strings.IndexByte(rule, '='); separator >= 0 {
+			name, param = rule[:separator], rule[separator+1:]
+		}
+		// Rules are applied to values of pointers, nil pointers are checked only by 'nonzero'.
+		checkedValue := value
+		if value.Kind() == reflect.Ptr && name != "nonzero" {
+			if value.IsNil() {
+				continue
+			}
+			checkedValue = value.Elem()
+		}
+		satisfied, err := checkRule(structValue, checkedValue, name, param)
+		if err == nil && !satisfied {
+			err = fmt.Errorf("%w '%s'", ErrorValidationFailed, rule)
+		} else if err != nil {
+			err = fmt.Errorf("%w '%s': %v", ErrorIncorrectValidationRule, rule, err)
+		}
+		if err != nil {
+			return newValidationError(c, path, value, err)
+		}
+	}
+	return nil
+}
+
+func newValidationError(c Config, path string, value reflect.Value, err error) error {
+	var data string
+	dataValue := value
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		dataValue = value.Elem()
+	}
+	switch dataValue.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array, reflect.Ptr, reflect.Interface:
+	default:
+		data = fmt.Sprint(dataValue.Interface())
+	}
+	return setPathErrorType(wrapPathError(c, path, data, err), value.Type().String())
+}
+
+// splitValidationRules splits tag into rules. Rule 'regexp' takes rest of tag because
+// pattern may contain commas.
+func splitValidationRules(tag string) []string {
+	var rules []string
+	for len(tag) > 0 {
+		if strings.HasPrefix(tag, "regexp=") {
+			return append(rules, tag)
+		}
+		rule := tag
+		if separator := strings.IndexByte(tag, ','); separator >= 0 {
+			rule, tag = tag[:separator], tag[separator+1:]
+		} else {
+			tag = ""
+		}
+		if rule = strings.TrimSpace(rule); len(rule) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func checkRule(structValue reflect.Value, value reflect.Value, name string, param string) (bool, error) {
+	switch name {
+	case "nonzero":
+		return !value.IsZero(), nil
+	case "min", "max", "len":
+		result, err := compareWithParam(value, param, name == "len")
+		if name == "min" {
+			return result >= 0, err
+		} else if name == "max" {
+			return result <= 0, err
+		}
+		return result == 0, err
+	case "oneof":
+		if !isScalar(value) {
+			return false, ErrorUnsupportedTypeToLoadValue
+		}
+		data := fmt.Sprint(value.Interface())
+		for _, allowed := range strings.Fields(param) {
+			if data == allowed {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "regexp":
+		if value.Kind() != reflect.String {
+			return false, ErrorUnsupportedTypeToLoadValue
+		}
+		pattern, err := regexp.Compile(param)
+		if err != nil {
+			return false, err
+		}
+		return pattern.MatchString(value.String()), nil
+	case "gtfield", "gtefield", "ltfield", "ltefield":
+		otherValue := structValue.FieldByName(param)
+		if !otherValue.IsValid() {
+			return false, ErrorNotFound
+		} else if otherValue.Kind() == reflect.Ptr {
+			if otherValue.IsNil() {
+				// Value cannot be compared with absent value.
+				return true, nil
+			}
+			otherValue = otherValue.Elem()
+		}
+		result, err := compareValues(value, otherValue)
+		switch name {
+		case "gtfield":
+			return result > 0, err
+		case "gtefield":
+			return result >= 0, err
+		case "ltfield":
+			return result < 0, err
+		}
+		return result <= 0, err
+	}
+	return false, errorUnknownValidationRule
+}
+
+// compareWithParam compares number or length (if 'length' is true or value is not a
+// number) of value with parameter of rule.
+func compareWithParam(value reflect.Value, param string, length bool) (int, error) {
+	if !length {
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if value.Type() == reflect.TypeOf(time.Duration(0)) {
+				if limit, err := ParseDuration(param); err == nil {
+					return compareOrdered(value.Int(), int64(limit)), nil
+				}
+			}
+			limit, err := strconv.ParseInt(param, 0, 64)
+			return compareOrdered(value.Int(), limit), err
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if value.Type() == reflect.TypeOf(ByteSize(0)) {
+				if limit, err := ParseByteSize(param); err == nil {
+					return compareOrdered(value.Uint(), uint64(limit)), nil
+				}
+			}
+			limit, err := strconv.ParseUint(param, 0, 64)
+			return compareOrdered(value.Uint(), limit), err
+		case reflect.Float32, reflect.Float64:
+			limit, err := strconv.ParseFloat(param, 64)
+			return compareOrdered(value.Float(), limit), err
+		}
+	}
+	valueLength, err := getLength(value)
+	if err != nil {
+		return 0, err
+	}
+	limit, err := strconv.Atoi(param)
+	return compareOrdered(valueLength, limit), err
+}
+
+func compareValues(value reflect.Value, otherValue reflect.Value) (int, error) {
+	if value.Type() != otherValue.Type() {
+		return 0, ErrorIncorrectValueType
+	}
+	if value.Type() == reflect.TypeOf(time.Time{}) {
+		valueTime, otherTime := value.Interface().(time.Time), otherValue.Interface().(time.Time)
+		if valueTime.Before(otherTime) {
+			return -1, nil
+		} else if valueTime.After(otherTime) {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(value.Int(), otherValue.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(value.Uint(), otherValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(value.Float(), otherValue.Float()), nil
+	case reflect.String:
+		return compareOrdered(value.String(), otherValue.String()), nil
+	}
+	return 0, ErrorUnsupportedTypeToLoadValue
+}
+
+func compareOrdered[T int | int64 | uint64 | float64 | string](value T, otherValue T) int {
+	if value < otherValue {
+		return -1
+	} else if value > otherValue {
+		return 1
+	}
+	return 0
+}
+
+func getLength(value reflect.Value) (int, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len(), nil
+	}
+	return 0, ErrorUnsupportedTypeToLoadValue
+}
+
+func isScalar(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+//Copyright 2016 lyobzik
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type validatedServerData struct {
+	Host    string        `config:"host" validate:"nonzero,regexp=^[a-z.]{1,}$"`
+	Port    uint16        `config:"port" validate:"min=1,max=65535"`
+	Mode    string        `config:"mode" validate:"oneof=debug release"`
+	Timeout time.Duration `config:"timeout" validate:"min=1s,max=1m"`
+	Tags    []string      `config:"tags" validate:"min=1,max=3"`
+	Code    string        `config:"code" validate:"len=3"`
+}
+
+type validatedRangeData struct {
+	Low  int `config:"low"`
+	High int `config:"high" validate:"gtfield=Low"`
+}
+
+var (
+	errorForTestValidation = errors.New("Low must not be negative")
+)
+
+func (d *validatedRangeData) Validate() error {
+	if d.Low < 0 {
+		return errorForTestValidation
+	}
+	return nil
+}
+
+type validatedData struct {
+	Server validatedServerData `config:"server"`
+	Range  validatedRangeData  `config:"range"`
+}
+
+const (
+	validJSONConfig = `{
+  "server": {"host": "localhost", "port": 80, "mode": "debug", "timeout": "10s",
+    "tags": ["web"], "code": "абв"},
+  "range": {"low": 1, "high": 2}
+}`
+)
+
+func loadValidatedData(t *testing.T, replacements map[string]interface{}) error {
+	config, err := CreateConfigFromString(validJSONConfig, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	for path, value := range replacements {
+		parts := splitPath(path)
+		data := config.(*jsonConfig).data.(map[string]interface{})
+		data[parts[0]].(map[string]interface{})[parts[1]] = value
+	}
+	var value validatedData
+	return LoadValue(config, "/", &value)
+}
+
+// Tests.
+func TestValidateCorrectValue(t *testing.T) {
+	require.NoError(t, loadValidatedData(t, nil))
+}
+
+func TestValidateRules(t *testing.T) {
+	for path, value := range map[string]interface{}{
+		"/server/host":    "",
+		"/server/port":    float64(0),
+		"/server/mode":    "trace",
+		"/server/timeout": "2m",
+		"/server/tags":    []interface{}{},
+		"/server/code":    "abcd",
+		"/range/high":     float64(1),
+	} {
+		err := loadValidatedData(t, map[string]interface{}{path: value})
+		require.ErrorIs(t, err, ErrorValidationFailed, path)
+
+		var pathError *PathError
+		require.ErrorAs(t, err, &pathError, path)
+		require.Equal(t, path, pathError.Path)
+	}
+
+	err := loadValidatedData(t, map[string]interface{}{"/server/host": "Local-Host"})
+	require.EqualError(t, err, "2:14: Value does not satisfy rule 'regexp=^[a-z.]{1,}$' "+
+		"by path '/server/host', value 'Local-Host', expected type string")
+}
+
+func TestValidateByInterface(t *testing.T) {
+	err := loadValidatedData(t, map[string]interface{}{"/range/low": float64(-2)})
+	require.ErrorIs(t, err, errorForTestValidation)
+	require.EqualError(t, err, "4:3: Low must not be negative by path '/range', "+
+		"expected type config.validatedRangeData")
+}
+
+func TestValidateCollectAllErrors(t *testing.T) {
+	config, err := CreateConfigFromString(`{"server": {"host": "", "port": 0, "mode": "debug",
+		"timeout": "1s", "tags": ["web"], "code": "abc"}, "range": {"low": 1, "high": 2}}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.CollectAllErrors = true
+
+	var value validatedData
+	err = TunedLoadValue(config, settings, "/", &value)
+	var loadErrors *LoadErrors
+	require.ErrorAs(t, err, &loadErrors)
+	require.Len(t, loadErrors.Errors, 2)
+
+	settings.SkipValidation = true
+	require.NoError(t, TunedLoadValue(config, settings, "/", &value))
+}
+
+func TestValidateIncorrectRules(t *testing.T) {
+	config, err := CreateConfigFromString(`{"value": 1, "name": "service"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var unknownRule struct {
+		Value int `config:"value" validate:"positive"`
+	}
+	err = LoadValue(config, "/", &unknownRule)
+	require.ErrorIs(t, err, ErrorIncorrectValidationRule)
+
+	var incorrectParam struct {
+		Value int `config:"value" validate:"min=one"`
+	}
+	err = LoadValue(config, "/", &incorrectParam)
+	require.ErrorIs(t, err, ErrorIncorrectValidationRule)
+
+	var unknownField struct {
+		Value int `config:"value" validate:"gtfield=Absent"`
+	}
+	err = LoadValue(config, "/", &unknownField)
+	require.ErrorIs(t, err, ErrorIncorrectValidationRule)
+
+	var incorrectType struct {
+		Value int    `config:"value" validate:"ltfield=Name"`
+		Name  string `config:"name"`
+	}
+	err = LoadValue(config, "/", &incorrectType)
+	require.ErrorIs(t, err, ErrorIncorrectValidationRule)
+}
+
+func TestValidatePointerFields(t *testing.T) {
+	type pointerData struct {
+		Port  *int    `config:"port,omitempty" validate:"min=1,max=65535"`
+		Name  *string `config:"name,omitempty" validate:"len=3"`
+		Limit *int    `config:"limit,omitempty" validate:"gtfield=Port"`
+		Mode  *string `config:"mode,omitempty" validate:"nonzero"`
+	}
+	config, err := CreateConfigFromString(`{"port": 80, "name": "api", "limit": 100, "mode": "on"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+	var value pointerData
+	require.NoError(t, LoadValue(config, "/", &value))
+	require.Equal(t, 80, *value.Port)
+
+	config, err = CreateConfigFromString(`{"port": 0, "mode": "on"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+	err = LoadValue(config, "/", &pointerData{})
+	require.ErrorIs(t, err, ErrorValidationFailed)
+	require.EqualError(t, err, "1:2: Value does not satisfy rule 'min=1' by path '/port', value '0', "+
+		"expected type *int")
+
+	config, err = CreateConfigFromString(`{"mode": "on"}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+	require.NoError(t, LoadValue(config, "/", &pointerData{}), "Nil pointers must be skipped")
+
+	config, err = CreateConfigFromString(`{}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+	err = LoadValue(config, "/", &pointerData{})
+	require.ErrorIs(t, err, ErrorValidationFailed, "Nil pointer must be checked by 'nonzero'")
+}
+
+type validatedPort int
+
+var (
+	errorForTestPortValidation = errors.New("Port must be positive")
+)
+
+func (p *validatedPort) Validate() error {
+	if *p <= 0 {
+		return errorForTestPortValidation
+	}
+	return nil
+}
+
+func TestValidateElementsOfCollections(t *testing.T) {
+	type collectionsData struct {
+		Servers map[string]validatedServerData `config:"servers"`
+		Ports   []validatedPort                `config:"ports"`
+		Pair    [2]validatedPort               `config:"pair"`
+	}
+	config, err := CreateConfigFromString(`{"servers": {"a": {"host": "localhost", "port": 0,
+		"mode": "debug", "timeout": "1s", "tags": ["web"], "code": "abc"}},
+		"ports": [80, 0], "pair": [-1, 443]}`, JSON)
+	require.NoError(t, err, "Cannot load config")
+
+	var value collectionsData
+	err = LoadValue(config, "/", &value)
+	require.ErrorIs(t, err, ErrorValidationFailed)
+	require.EqualError(t, err, "1:41: Value does not satisfy rule 'min=1' by path '/servers/a/port', "+
+		"value '0', expected type uint16")
+
+	settings := GetDefaultLoadSettings(false)
+	settings.CollectAllErrors = true
+	err = TunedLoadValue(config, settings, "/", &value)
+	var loadErrors *LoadErrors
+	require.ErrorAs(t, err, &loadErrors)
+	require.Len(t, loadErrors.Errors, 3)
+	require.EqualError(t, loadErrors.Errors[1], "Port must be positive by path '/ports/1', "+
+		"value '0', expected type config.validatedPort")
+	require.EqualError(t, loadErrors.Errors[2], "Port must be positive by path '/pair/0', "+
+		"value '-1', expected type config.validatedPort")
+}
@@ -15,22 +15,237 @@
 package config
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	ini "gopkg.in/ini.v1"
 )
 
+const (
+	// iniSectionDelimiter separates names of nested sections like '[db.replica]'.
+	iniSectionDelimiter = "."
+	// iniParentDelimiter separates name of section and name of inherited section like
+	// '[prod : base]'.
+	iniParentDelimiter = ":"
+	// iniListSuffix marks keys that are lists even with single value like 'path[] = /bin'.
+	iniListSuffix = "[]"
+)
+
+var (
+	// iniLoadOptions keeps all values of repeated keys. Values may be continued on next lines
+	// after backslash or enclosed in triple quotes.
+	iniLoadOptions = ini.LoadOptions{AllowShadows: true, AllowDuplicateShadowValues: true}
+)
+
+// iniKey is key of section with values of all its occurrences. Repeated keys and keys with
+// suffix '[]' are lists, their values are not split by delimiters.
+type iniKey struct {
+	Name   string
+	Values []string
+	IsList bool
+}
+
+// Add adds values of key to values of previous occurrences of key with the same name.
+func (k *iniKey) Add(key *ini.Key) {
+	isList := strings.HasSuffix(key.Name(), iniListSuffix)
+	values := key.ValueWithShadows()
+	if len(values) == 0 && !isList {
+		values = []string{key.Value()}
+	}
+	k.Values = append(k.Values, values...)
+	k.IsList = k.IsList || isList || len(k.Values) > 1
+}
+
+// String returns the last value of key like it overrides previous ones.
+func (k *iniKey) String() string {
+	if len(k.Values) == 0 {
+		return ""
+	}
+	return k.Values[len(k.Values)-1]
+}
+
+// Value returns value of key as list or as string.
+func (k *iniKey) Value() interface{} {
+	if !k.IsList {
+		return k.String()
+	}
+	values := make([]interface{}, len(k.Values))
+	for i, value := range k.Values {
+		values[i] = value
+	}
+	return values
+}
+
+// iniSection is node of sections tree. Keys of default section are stored in root of tree,
+// dotted names of sections are split into nested sections.
+type iniSection struct {
+	// Section of config (nil for sections that are only declared by nested ones).
+	Section *ini.Section
+	Keys    map[string]*iniKey
+	// Names of keys in order of their definition.
+	Names    []string
+	Parent   *iniSection
+	Children map[string]*iniSection
+}
+
+func newINISection() *iniSection {
+	return &iniSection{Keys: make(map[string]*iniKey), Children: make(map[string]*iniSection)}
+}
+
+// AddKey adds key of config section. Keys 'name' and 'name[]' are the same key.
+func (s *iniSection) AddKey(key *ini.Key) {
+	name := strings.TrimSuffix(key.Name(), iniListSuffix)
+	sectionKey, exist := s.Keys[name]
+	if !exist {
+		sectionKey = &iniKey{Name: name}
+		s.Keys[name], s.Names = sectionKey, append(s.Names, name)
+	}
+	sectionKey.Add(key)
+}
+
+// FindKey returns key of section or of inherited sections and section that contains it.
+func (s *iniSection) FindKey(name string) (*iniKey, *iniSection) {
+	for section := s; section != nil; section = section.Parent {
+		if key, exist := section.Keys[name]; exist {
+			return key, section
+		}
+	}
+	return nil, nil
+}
+
+// KeyNames returns names of own and inherited keys of section in order of their definition.
+func (s *iniSection) KeyNames() []string {
+	names := make([]string, 0, len(s.Keys))
+	added := make(map[string]bool, len(s.Keys))
+	for section := s; section != nil; section = section.Parent {
+		for _, name := range section.Names {
+			if !added[name] {
+				names, added[name] = append(names, name), true
+			}
+		}
+	}
+	return names
+}
+
+// ChildNames returns sorted names of nested sections.
+func (s *iniSection) ChildNames() []string {
+	names := make([]string, 0, len(s.Children))
+	for name := range s.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildINISections builds tree of sections. Section '[prod : base]' is stored by path
+// '/prod' and inherits keys of section '[base]' that are not overridden.
+func buildINISections(file *ini.File, positions iniPositions) (*iniSection, error) {
+	root := newINISection()
+	parents := make(map[*iniSection]string)
+	for _, section := range file.Sections() {
+		node := root
+		name := section.Name()
+		if name != ini.DefaultSection {
+			parentName := ""
+			if separator := strings.Index(name, iniParentDelimiter); separator >= 0 {
+				name, parentName = strings.TrimSpace(name[:separator]), strings.TrimSpace(name[separator+1:])
+			}
+			node = getINISection(root, name)
+			if len(parentName) != 0 {
+				parents[node] = parentName
+			}
+		}
+		if node.Section != nil {
+			return nil, newINISectionError(positions, section, "duplicate section %q", name)
+		}
+		node.Section = section
+		for _, key := range section.Keys() {
+			node.AddKey(key)
+		}
+	}
+
+	for node, parentName := range parents {
+		parent := findINISection(root, parentName)
+		if parent == nil || parent.Section == nil {
+			return nil, newINISectionError(positions, node.Section, "unknown parent section %q", parentName)
+		}
+		node.Parent = parent
+	}
+	for node := range parents {
+		visited := map[*iniSection]bool{}
+		for parent := node; parent != nil; parent = parent.Parent {
+			if visited[parent] {
+				return nil, newINISectionError(positions, node.Section, "cyclic inheritance of sections")
+			}
+			visited[parent] = true
+		}
+	}
+	return root, nil
+}
+
+// getINISection returns nested section by dotted name, absent sections are created.
+func getINISection(root *iniSection, name string) *iniSection {
+	node := root
+	for _, part := range splitINISectionName(name) {
+		child, exist := node.Children[part]
+		if !exist {
+			child = newINISection()
+			node.Children[part] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// findINISection returns nested section by dotted name or nil if it is absent.
+func findINISection(root *iniSection, name string) *iniSection {
+	node := root
+	for _, part := range splitINISectionName(name) {
+		if node = node.Children[part]; node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func splitINISectionName(name string) []string {
+	parts := strings.Split(name, iniSectionDelimiter)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return filterPathParts(parts)
+}
+
+func newINISectionError(positions iniPositions, section *ini.Section, format string, args ...interface{}) error {
+	parseError := &ParseError{Err: fmt.Errorf(format, args...)}
+	if sectionPositions, exist := positions[section.Name()]; exist {
+		parseError.Position = sectionPositions.Position
+	}
+	return parseError
+}
+
 type iniConfig struct {
-	file    *ini.File
-	section *ini.Section
-	key     *ini.Key
+	// Section of config part or section that contains key of config part.
+	section   *iniSection
+	key       *iniKey
+	fileName  string
+	positions iniPositions
+	// Flag that specifies whether to match names after normalization.
+	normalizeKeys bool
 }
 
 func newINIConfig(data []byte) (Config, error) {
-	file, err := ini.Load(data)
+	file, err := ini.LoadSources(iniLoadOptions, data)
+	if err != nil {
+		return nil, newINIParseError(data, err)
+	}
+	positions := indexINIPositions(data)
+	root, err := buildINISections(file, positions)
 	if err != nil {
 		return nil, err
 	}
-
-	return &iniConfig{file: file}, nil
+	return &iniConfig{section: root, positions: positions}, nil
 }
 
 // Grabbers.
@@ -48,46 +263,56 @@ func (c *iniConfig) GrabValues(path string, delim string,
 func (c *iniConfig) GetString(path string) (value string, err error) {
 	key, err := c.findKey(path)
 	if err != nil {
-		return value, err
+		return value, setPathErrorType(err, "string")
 	}
 	return key.String(), nil
 }
 
 func (c *iniConfig) GetBool(path string) (value bool, err error) {
-	key, err := c.findKey(path)
-	if err != nil {
-		return value, err
-	}
-	return key.Bool()
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = parseINIBool(data)
+		return err
+	}), "bool")
 }
 
 func (c *iniConfig) GetFloat(path string) (value float64, err error) {
-	key, err := c.findKey(path)
-	if err != nil {
-		return value, err
-	}
-	return key.Float64()
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = parseINIFloat(data)
+		return err
+	}), "float64")
 }
 
 func (c *iniConfig) GetInt(path string) (value int64, err error) {
-	key, err := c.findKey(path)
-	if err != nil {
-		return value, err
-	}
-	return key.Int64()
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = parseINIInt(data)
+		return err
+	}), "int64")
+}
+
+func (c *iniConfig) GetUint(path string) (value uint64, err error) {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = parseINIUint(data)
+		return err
+	}), "uint64")
 }
 
 // Get array of values.
 func (c *iniConfig) GetStrings(path string, delim string) (value []string, err error) {
 	key, err := c.findKey(path)
 	if err != nil {
-		return value, err
+		return value, setPathErrorType(err, "[]string")
+	}
+	if key.IsList {
+		return append([]string{}, key.Values...), nil
 	}
-	return key.Strings(delim), nil
+	if value, err = SplitList(key.String(), delim, defaultListSettings); err != nil {
+		return value, setPathErrorType(wrapPathError(c, path, key.String(), err), "[]string")
+	}
+	return value, nil
 }
 
 func (c *iniConfig) GetBools(path string, delim string) (value []bool, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]bool, 0, cap) },
 		func(data string) error {
 			var parsed bool
@@ -95,11 +320,11 @@ func (c *iniConfig) GetBools(path string, delim string) (value []bool, err error
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]bool")
 }
 
 func (c *iniConfig) GetFloats(path string, delim string) (value []float64, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]float64, 0, cap) },
 		func(data string) error {
 			var parsed float64
@@ -107,11 +332,11 @@ func (c *iniConfig) GetFloats(path string, delim string) (value []float64, err e
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]float64")
 }
 
 func (c *iniConfig) GetInts(path string, delim string) (value []int64, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]int64, 0, cap) },
 		func(data string) error {
 			var parsed int64
@@ -119,81 +344,226 @@ func (c *iniConfig) GetInts(path string, delim string) (value []int64, err error
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]int64")
+}
+
+func (c *iniConfig) GetUints(path string, delim string) (value []uint64, err error) {
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
+		func(cap int) { value = make([]uint64, 0, cap) },
+		func(data string) error {
+			var parsed uint64
+			if parsed, err = parseINIUint(data); err == nil {
+				value = append(value, parsed)
+			}
+			return err
+		}), "[]uint64")
+}
+
+func (c *iniConfig) GetIntRanges(path string, delim string) ([]int64, error) {
+	return getIntRanges(c, path, delim)
 }
 
 // Get subconfig.
 func (c *iniConfig) GetConfigPart(path string) (Config, error) {
 	section, key, err := c.findElement(path)
 	if err != nil {
-		return nil, err
+		return nil, wrapPathError(c, path, "", err)
+	}
+	return &iniConfig{section: section, key: key, fileName: c.fileName, positions: c.positions,
+		normalizeKeys: c.normalizeKeys}, nil
+}
+
+// Get position of value.
+func (c *iniConfig) Position(path string) (Position, error) {
+	section, key, err := c.findElement(path)
+	if err == nil && section.Section == nil {
+		err = ErrorNotFound
+	}
+	var position Position
+	if err == nil {
+		sectionPositions := c.positions[section.Section.Name()]
+		position = sectionPositions.Position
+		if key != nil {
+			position = sectionPositions.Keys[key.Name]
+		}
+		if !position.IsValid() {
+			err = ErrorNotFound
+		}
 	}
-	if section == nil && key == nil {
-		return &iniConfig{file: c.file}, nil
+	if err != nil {
+		return Position{}, &PathError{Path: joinPath(path), Err: err}
 	}
-	return &iniConfig{section: section, key: key}, nil
+	position.File = c.fileName
+	return position, nil
 }
 
 // Ini helpers.
-func (c *iniConfig) findKey(path string) (*ini.Key, error) {
-	_, key, err := c.findElement(path)
-	if err != nil {
-		return nil, err
+func (c *iniConfig) sourceFile() string {
+	return c.fileName
+}
+
+func (c *iniConfig) setSourceFile(file string) {
+	c.fileName = file
+}
+
+func (c *iniConfig) walkValues(visit valueVisitor) {
+	if c.key != nil {
+		visit(pathDelimiter, c.key.Value())
+	} else {
+		walkINISection(c.section, pathDelimiter, visit)
+	}
+}
+
+// iniSectionPositions contains positions of section header and keys of section.
+type iniSectionPositions struct {
+	Position
+	Keys map[string]Position
+}
+
+// iniPositions contains positions of sections by their names.
+type iniPositions map[string]*iniSectionPositions
+
+// indexINIPositions scans config source line by line because parser does not keep
+// positions. Only first occurrence of key is recorded.
+func indexINIPositions(data []byte) iniPositions {
+	section := &iniSectionPositions{Keys: make(map[string]Position)}
+	positions := iniPositions{ini.DefaultSection: section}
+	for number, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || trimmed[0] == '#' || trimmed[0] == ';' {
+			continue
+		}
+		position := Position{Line: number + 1, Column: strings.Index(line, trimmed) + 1}
+		if trimmed[0] == '[' {
+			if end := strings.IndexByte(trimmed, ']'); end > 0 {
+				name := strings.TrimSpace(trimmed[1:end])
+				if section = positions[name]; section == nil {
+					section = &iniSectionPositions{Keys: make(map[string]Position)}
+					positions[name] = section
+				}
+				if !section.IsValid() {
+					section.Position = position
+				}
+			}
+			continue
+		}
+		if end := strings.IndexAny(trimmed, "=:"); end > 0 {
+			name := strings.Trim(strings.TrimSpace(trimmed[:end]), "\"`")
+			name = strings.TrimSuffix(name, iniListSuffix)
+			if _, exist := section.Keys[name]; !exist {
+				section.Keys[name] = position
+			}
+		}
 	}
-	if key == nil {
-		return nil, ErrorNotFound
+	return positions
+}
+
+// newINIParseError finds position of error by line of source that is specified in message.
+func newINIParseError(data []byte, err error) error {
+	parseError := &ParseError{Err: err}
+	message := err.Error()
+	separator := strings.Index(message, ": ")
+	if separator < 0 {
+		return parseError
 	}
-	return key, nil
+	text := strings.TrimSpace(message[separator+2:])
+	for number, line := range strings.Split(string(data), "\n") {
+		if len(text) > 0 && strings.TrimSpace(line) == text {
+			parseError.Position = Position{Line: number + 1, Column: strings.Index(line, text) + 1}
+			break
+		}
+	}
+	return parseError
 }
 
-func (c *iniConfig) findElement(path string) (*ini.Section, *ini.Key, error) {
-	pathParts := splitPath(path)
-	section, pathParts, err := c.getSection(c.file, pathParts)
-	if err != nil {
-		return nil, nil, err
+func (c *iniConfig) valueTree() interface{} {
+	if c.key != nil {
+		return c.key.Value()
 	}
-	key, pathParts, err := c.getKey(section, pathParts)
-	if err != nil {
-		return nil, nil, err
+	return iniSectionTree(c.section)
+}
+
+// iniSectionTree returns keys and nested sections of section as tree of values. Nested
+// sections override keys with the same names.
+func iniSectionTree(section *iniSection) map[string]interface{} {
+	tree := make(map[string]interface{}, len(section.Keys)+len(section.Children))
+	for _, name := range section.KeyNames() {
+		key, _ := section.FindKey(name)
+		tree[name] = key.Value()
 	}
-	if len(pathParts) > 0 {
-		return nil, nil, ErrorNotFound
+	for name, child := range section.Children {
+		tree[name] = iniSectionTree(child)
 	}
-	return section, key, nil
+	return tree
+}
+
+func walkINISection(section *iniSection, path string, visit valueVisitor) {
+	for _, name := range section.KeyNames() {
+		if _, exist := section.Children[name]; !exist {
+			key, _ := section.FindKey(name)
+			visit(joinPath(path, name), key.Value())
+		}
+	}
+	for _, name := range section.ChildNames() {
+		walkINISection(section.Children[name], joinPath(path, name), visit)
+	}
+}
+
+func (c *iniConfig) listValue(path string) ([]string, bool) {
+	_, key, err := c.findElement(path)
+	if err != nil || key == nil || !key.IsList {
+		return nil, false
+	}
+	return key.Values, true
 }
 
-func (c *iniConfig) getSection(file *ini.File, path []string) (*ini.Section, []string, error) {
-	if c.section != nil {
-		return c.section, path, nil
+func (c *iniConfig) findKey(path string) (*iniKey, error) {
+	_, key, err := c.findElement(path)
+	if err == nil && key == nil {
+		err = ErrorNotFound
+	}
+	if err != nil {
+		return nil, wrapPathError(c, path, "", err)
 	}
-	if file != nil && len(path) > 0 {
-		var section *ini.Section
-		if len(file.Sections()) == 1 {
-			section, _ = file.GetSection("")
-		} else if len(path) > 0 {
-			section, _ = file.GetSection(path[0])
-			path = path[1:]
+	return key, nil
+}
+
+// findElement returns section or key by path. Nested sections are preferred to keys with
+// the same names. Section that contains key is returned with key.
+func (c *iniConfig) findElement(path string) (*iniSection, *iniKey, error) {
+	section, key := c.section, c.key
+	for _, pathPart := range splitPath(path) {
+		if key != nil {
+			return nil, nil, ErrorNotFound
 		}
-		if section == nil {
+		pathPart, err := c.findName(section, pathPart)
+		if err != nil {
+			return nil, nil, err
+		}
+		if child, exist := section.Children[pathPart]; exist {
+			section = child
+		} else if key, section = section.FindKey(pathPart); key == nil {
 			return nil, nil, ErrorNotFound
 		}
-		return section, path, nil
 	}
-	return nil, path, nil
+	return section, key, nil
 }
 
-func (c *iniConfig) getKey(section *ini.Section, path []string) (*ini.Key, []string, error) {
-	if c.key != nil {
-		return c.key, path, nil
+// findName returns name of nested section or key written in config that matches name of path.
+func (c *iniConfig) findName(section *iniSection, name string) (string, error) {
+	if !c.normalizeKeys {
+		return name, nil
 	}
-	if section != nil && len(path) > 0 {
-		key, _ := section.GetKey(path[0])
-		if key == nil {
-			return nil, nil, ErrorNotFound
-		}
-		return key, path[1:], nil
+	if childName, err := findNormalizedKey(section.ChildNames(), name); err != ErrorNotFound {
+		return childName, err
 	}
-	return nil, path, nil
+	return findNormalizedKey(section.KeyNames(), name)
+}
+
+func (c *iniConfig) withNormalizedKeys() Config {
+	config := *c
+	config.normalizeKeys = true
+	return &config
 }
 
 // Ini value parsers.
@@ -209,6 +579,10 @@ func parseINIInt(data string) (int64, error) {
 	return parseXMLInt(data)
 }
 
+func parseINIUint(data string) (uint64, error) {
+	return parseXMLUint(data)
+}
+
 // Grabbing helpers.
 func createINIValueGrabber(grabber ValueGrabber) StringValueGrabber {
 	return createXMLValueGrabber(grabber)
@@ -171,7 +171,7 @@ func TestIniGetValueEmptyPath(t *testing.T) {
 
 	for _, functors := range elementFunctors {
 		_, err = functors.Getter(config, "")
-		require.EqualError(t, err, ErrorNotFound.Error())
+		require.ErrorIs(t, err, ErrorNotFound)
 	}
 }
 
@@ -274,7 +274,7 @@ func TestIniGrabAbsentValue(t *testing.T) {
 		return nil
 	})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -290,7 +290,7 @@ func TestIniGrabAbsentValues(t *testing.T) {
 			return nil
 		})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -303,7 +303,7 @@ func TestIniGrabValuePassError(t *testing.T) {
 		return expectedError
 	})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 func TestIniGrabValuesPassError(t *testing.T) {
@@ -317,7 +317,7 @@ func TestIniGrabValuesPassError(t *testing.T) {
 			return expectedError
 		})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 // Parser tests.
@@ -355,6 +355,22 @@ func TestParseIniInt(t *testing.T) {
 	require.EqualError(t, err, ErrorIncorrectValueType.Error())
 }
 
+func TestIniGetUint(t *testing.T) {
+	config, err := newINIConfig([]byte(oneLevelINIConfig))
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	value, err := config.GetUint("/intElement")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(expectedIntValue), value)
+
+	values, err := config.GetUints("/intElements", defaultArrayDelimiter)
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, []uint64{123, 456, 789}, values)
+
+	_, err = config.GetUint("/stringElement")
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+}
+
 // Test GetConfigPart
 func TestIniGetConfigPartRootFromRoot(t *testing.T) {
 	rootConfig, err := newINIConfig([]byte(twoLevelINIConfig))
@@ -462,13 +478,13 @@ func TestIniGetConfigPartWithLongPath(t *testing.T) {
 	require.NoError(t, err, "Cannot get config key ection")
 
 	_, err = rootConfig.GetConfigPart("/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 
 	_, err = configSection.GetConfigPart("/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 
 	_, err = configKey.GetConfigPart("/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 }
 
 func TestIniGetConfigPartAbsentSectionFromTwoLevelRoot(t *testing.T) {
@@ -476,7 +492,7 @@ func TestIniGetConfigPartAbsentSectionFromTwoLevelRoot(t *testing.T) {
 	require.NoError(t, err, "Cannot parse root ini-config")
 
 	_, err = rootConfig.GetConfigPart("/third")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 }
 
 func TestIniGetConfigPartAbsentKeyFromTwoLevelRoot(t *testing.T) {
@@ -484,10 +500,10 @@ func TestIniGetConfigPartAbsentKeyFromTwoLevelRoot(t *testing.T) {
 	require.NoError(t, err, "Cannot parse root ini-config")
 
 	_, err = rootConfig.GetConfigPart("/first/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 
 	_, err = rootConfig.GetConfigPart("/third/stringElement")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 }
 
 func TestIniGetConfigPartAbsentKeyFromSection(t *testing.T) {
@@ -498,5 +514,132 @@ func TestIniGetConfigPartAbsentKeyFromSection(t *testing.T) {
 	require.NoError(t, err, "Cannot get config section")
 
 	_, err = configSection.GetConfigPart("element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
+}
+
+func TestIniDefaultSectionWithSections(t *testing.T) {
+	config, err := newINIConfig([]byte("name = main\n[server]\nhost = localhost\n"))
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	name, err := config.GetString("/name")
+	require.NoError(t, err, "Keys of default section must be in root")
+	require.Equal(t, "main", name)
+
+	host, err := config.GetString("/server/host")
+	require.NoError(t, err)
+	require.Equal(t, "localhost", host)
+}
+
+func TestIniNestedSections(t *testing.T) {
+	config, err := newINIConfig([]byte("[db]\nhost = db1\nport = 5432\n" +
+		"[db.replica]\nhost = db2\n[cache . local]\nsize = 10\n"))
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	for path, expected := range map[string]string{
+		"/db/host":          "db1",
+		"/db/replica/host":  "db2",
+		"/cache/local/size": "10",
+	} {
+		value, err := config.GetString(path)
+		require.NoError(t, err, path)
+		require.Equal(t, expected, value, path)
+	}
+	_, err = config.GetString("/db/replica/port")
+	require.ErrorIs(t, err, ErrorNotFound, "Nested section must not inherit keys implicitly")
+
+	replica, err := config.GetConfigPart("/db/replica")
+	require.NoError(t, err, "Cannot get nested section")
+	host, err := replica.GetString("/host")
+	require.NoError(t, err)
+	require.Equal(t, "db2", host)
+
+	var value struct {
+		DB struct {
+			Host    string `config:"host"`
+			Replica struct {
+				Host string `config:"host"`
+			} `config:"replica"`
+		} `config:"db"`
+	}
+	require.NoError(t, LoadValueIgnoringMissingFieldErrors(config, "/", &value))
+	require.Equal(t, "db1", value.DB.Host)
+	require.Equal(t, "db2", value.DB.Replica.Host)
+
+	checkPosition(t, config, "/db/replica/host", 5, 1)
+	_, err = config.Position("/cache")
+	require.ErrorIs(t, err, ErrorNotFound, "Section without header has no position")
+}
+
+func TestIniSectionInheritance(t *testing.T) {
+	config, err := newINIConfig([]byte("[base]\nhost = localhost\nport = 80\n" +
+		"[prod : base]\nhost = example.com\n"))
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	var value struct {
+		Host string `config:"host"`
+		Port int    `config:"port"`
+	}
+	require.NoError(t, LoadValue(config, "/prod", &value))
+	require.Equal(t, "example.com", value.Host)
+	require.Equal(t, 80, value.Port)
+	checkPosition(t, config, "/prod/port", 3, 1)
+
+	var tree interface{}
+	require.NoError(t, LoadValue(config, "/prod", &tree))
+	require.Equal(t, map[string]interface{}{"host": "example.com", "port": "80"}, tree)
+
+	_, err = newINIConfig([]byte("[prod : absent]\nhost = example.com\n"))
+	require.Error(t, err, "Unknown parent section must be reported")
+	_, err = newINIConfig([]byte("[a : b]\nx = 1\n[b : a]\ny = 2\n"))
+	require.Error(t, err, "Cyclic inheritance must be reported")
+}
+
+func TestIniRepeatedKeys(t *testing.T) {
+	config, err := newINIConfig([]byte("[Service]\nExecStartPre=/bin/mkdir -p /run/app\n" +
+		"ExecStartPre=/bin/chown app /run/app\nUser=app\nUser=daemon\n" +
+		"[php]\nextension[] = curl\nports[] = 80\nports[] = 8000-8001\nlimits = 1 2\n"))
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	commands, err := config.GetStrings("/Service/ExecStartPre", " ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/bin/mkdir -p /run/app", "/bin/chown app /run/app"}, commands,
+		"Values of repeated key must not be split")
+	user, err := config.GetString("/Service/User")
+	require.NoError(t, err)
+	require.Equal(t, "daemon", user, "The last value of repeated key must be returned")
+
+	extensions, err := config.GetStrings("/php/extension", ",")
+	require.NoError(t, err)
+	require.Equal(t, []string{"curl"}, extensions)
+	limits, err := config.GetInts("/php/limits", " ")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, limits)
+
+	var value struct {
+		Commands []string `config:"Service/ExecStartPre"`
+		Ports    []uint16 `config:"php/ports"`
+	}
+	require.NoError(t, LoadValueIgnoringMissingFieldErrors(config, "/", &value))
+	require.Equal(t, []string{"/bin/mkdir -p /run/app", "/bin/chown app /run/app"}, value.Commands)
+	require.Equal(t, []uint16{80, 8000, 8001}, value.Ports)
+
+	var tree interface{}
+	require.NoError(t, LoadValue(config, "/php", &tree))
+	require.Equal(t, map[string]interface{}{"extension": []interface{}{"curl"},
+		"ports": []interface{}{"80", "8000-8001"}, "limits": "1 2"}, tree)
+	checkPosition(t, config, "/php/ports", 8, 1)
+}
+
+func TestIniMultilineValues(t *testing.T) {
+	config, err := newINIConfig([]byte("command = run \\\n  --verbose\n" +
+		"script = \"\"\"first line\nsecond line\"\"\"\n"))
+	require.NoError(t, err, "Cannot parse ini-config")
+
+	command, err := config.GetString("/command")
+	require.NoError(t, err)
+	require.Equal(t, "run --verbose", command)
+
+	script, err := config.GetString("/script")
+	require.NoError(t, err)
+	require.Equal(t, "first line\nsecond line", script)
 }
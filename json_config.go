@@ -15,21 +15,33 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
-	"math"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
 )
 
 type jsonConfig struct {
-	data interface{}
+	data      interface{}
+	file      string
+	positions positionIndex
+	prefix    string
+	// Flag that specifies whether to match keys after normalization.
+	normalizeKeys bool
 }
 
 func newJSONConfig(data []byte) (Config, error) {
 	var config jsonConfig
 
-	if err := json.Unmarshal(data, &config.data); err != nil {
-		return nil, err
+	var err error
+	if config.data, err = decodeJSON(data); err != nil {
+		return nil, newJSONParseError(data, err)
 	}
+	config.positions = indexJSONPositions(data)
 	return &config, nil
 }
 
@@ -37,9 +49,10 @@ func newJSONConfig(data []byte) (Config, error) {
 func (c *jsonConfig) GrabValue(path string, grabber ValueGrabber) (err error) {
 	element, err := c.findElement(path)
 	if err != nil {
-		return err
+		return wrapPathError(c, path, "", err)
 	}
-	return grabber(element)
+	value, _ := parseJSONString(element)
+	return wrapPathError(c, path, value, grabber(element))
 }
 
 func (c *jsonConfig) GrabValues(path string, delim string,
@@ -50,36 +63,43 @@ func (c *jsonConfig) GrabValues(path string, delim string,
 
 // Get single value.
 func (c *jsonConfig) GetString(path string) (value string, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseJSONString(data)
 		return err
-	})
+	}), "string")
 }
 
 func (c *jsonConfig) GetBool(path string) (value bool, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseJSONBool(data)
 		return err
-	})
+	}), "bool")
 }
 
 func (c *jsonConfig) GetFloat(path string) (value float64, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseJSONFloat(data)
 		return err
-	})
+	}), "float64")
 }
 
 func (c *jsonConfig) GetInt(path string) (value int64, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseJSONInt(data)
 		return err
-	})
+	}), "int64")
+}
+
+func (c *jsonConfig) GetUint(path string) (value uint64, err error) {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
+		value, err = parseJSONUint(data)
+		return err
+	}), "uint64")
 }
 
 // Get array of values.
 func (c *jsonConfig) GetStrings(path string, delim string) (value []string, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]string, 0, cap) },
 		func(data interface{}) error {
 			var parsed string
@@ -87,11 +107,11 @@ func (c *jsonConfig) GetStrings(path string, delim string) (value []string, err
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]string")
 }
 
 func (c *jsonConfig) GetBools(path string, delim string) (value []bool, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]bool, 0, cap) },
 		func(data interface{}) error {
 			var parsed bool
@@ -99,11 +119,11 @@ func (c *jsonConfig) GetBools(path string, delim string) (value []bool, err erro
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]bool")
 }
 
 func (c *jsonConfig) GetFloats(path string, delim string) (value []float64, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]float64, 0, cap) },
 		func(data interface{}) error {
 			var parsed float64
@@ -111,11 +131,11 @@ func (c *jsonConfig) GetFloats(path string, delim string) (value []float64, err
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]float64")
 }
 
 func (c *jsonConfig) GetInts(path string, delim string) (value []int64, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]int64, 0, cap) },
 		func(data interface{}) error {
 			var parsed int64
@@ -123,7 +143,23 @@ func (c *jsonConfig) GetInts(path string, delim string) (value []int64, err erro
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]int64")
+}
+
+func (c *jsonConfig) GetUints(path string, delim string) (value []uint64, err error) {
+	return value, setPathErrorType(c.GrabValues(path, delim,
+		func(cap int) { value = make([]uint64, 0, cap) },
+		func(data interface{}) error {
+			var parsed uint64
+			if parsed, err = parseJSONUint(data); err == nil {
+				value = append(value, parsed)
+			}
+			return err
+		}), "[]uint64")
+}
+
+func (c *jsonConfig) GetIntRanges(path string, delim string) ([]int64, error) {
+	return getIntRanges(c, path, delim)
 }
 
 // Get subconfig.
@@ -133,29 +169,127 @@ func (c *jsonConfig) GetConfigPart(path string) (Config, error) {
 	}
 	element, err := c.findElement(path)
 	if err != nil {
-		return nil, err
+		return nil, wrapPathError(c, path, "", err)
 	}
-	return &jsonConfig{data: element}, nil
+	return &jsonConfig{data: element, file: c.file, positions: c.positions,
+		prefix: concatPaths(c.prefix, c.resolvePath(path)), normalizeKeys: c.normalizeKeys}, nil
+}
+
+// Get position of value.
+func (c *jsonConfig) Position(path string) (Position, error) {
+	return findPosition(c, c.positions, c.prefix, c.resolvePath(path))
 }
 
 // Json helpers.
+func (c *jsonConfig) sourceFile() string {
+	return c.file
+}
+
+func (c *jsonConfig) setSourceFile(file string) {
+	c.file = file
+}
+
+func (c *jsonConfig) typedValue(path string) (interface{}, error) {
+	return c.findElement(path)
+}
+
+func (c *jsonConfig) valueTree() interface{} {
+	return newValueTree(c.data)
+}
+
+func (c *jsonConfig) walkValues(visit valueVisitor) {
+	if c.data != nil {
+		walkTree(c.data, pathDelimiter, visit)
+	}
+}
+
+func (c *jsonConfig) loadRawValue(path string, value reflect.Value) (bool, error) {
+	element := c.data
+	if len(splitPath(path)) != 0 {
+		var err error
+		if element, err = c.findElement(path); err != nil {
+			return false, nil
+		}
+	}
+	unmarshalerType := reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	if !useRawValue(value.Type(), unmarshalerType, element, isJSONStructure) {
+		return false, nil
+	}
+	data, err := json.Marshal(element)
+	if err == nil {
+		err = json.Unmarshal(data, value.Addr().Interface())
+	}
+	return true, wrapPathError(c, path, "", err)
+}
+
+func isJSONStructure(data interface{}) bool {
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
 func (c *jsonConfig) findElement(path string) (interface{}, error) {
+	element, _, err := c.resolveElement(path)
+	return element, err
+}
+
+// resolveElement returns element and path to it with keys written in config.
+func (c *jsonConfig) resolveElement(path string) (interface{}, string, error) {
 	element := c.data
 	pathParts := splitPath(path)
-	if len(pathParts) == 0 {
-		return nil, ErrorNotFound
+	if _, isSection := element.(map[string]interface{}); isSection && len(pathParts) == 0 {
+		// Only scalar values and lists may be addressed by empty path.
+		return nil, "", ErrorNotFound
 	}
-	for _, pathPart := range pathParts {
+	for i, pathPart := range pathParts {
 		part, converted := element.(map[string]interface{})
 		if !converted {
-			return nil, ErrorNotFound
+			return nil, "", ErrorNotFound
+		}
+		if c.normalizeKeys {
+			key, err := findNormalizedKey(sortedKeys(part), pathPart)
+			if err != nil {
+				return nil, "", err
+			}
+			pathParts[i] = key
 		}
 		var exist bool
-		if element, exist = part[pathPart]; !exist {
-			return nil, ErrorNotFound
+		if element, exist = part[pathParts[i]]; !exist {
+			return nil, "", ErrorNotFound
+		}
+	}
+	return element, joinPath(pathParts...), nil
+}
+
+// resolvePath returns path with keys written in config if keys are normalized.
+func (c *jsonConfig) resolvePath(path string) string {
+	if c.normalizeKeys {
+		if _, resolved, err := c.resolveElement(path); err == nil {
+			return resolved
 		}
 	}
-	return element, nil
+	return path
+}
+
+func (c *jsonConfig) withNormalizedKeys() Config {
+	config := *c
+	config.normalizeKeys = true
+	return &config
+}
+
+// decodeJSON decodes JSON keeping numbers as 'json.Number', so integers are not rounded.
+func decodeJSON(data []byte) (value interface{}, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&value); err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			return value, nil
+		}
+	}
+	// Errors of decoder differ from errors of Unmarshal for empty input and trailing data.
+	return nil, json.Unmarshal(data, &value)
 }
 
 // Json value parsers.
@@ -163,6 +297,8 @@ func parseJSONString(data interface{}) (value string, err error) {
 	switch dataValue := data.(type) {
 	case string:
 		return dataValue, nil
+	case json.Number:
+		return dataValue.String(), nil
 	case int, uint, int8, uint8, int16, uint16, int32, uint32, int64, uint64:
 		return fmt.Sprintf("%d", dataValue), nil
 	case float32, float64:
@@ -182,8 +318,12 @@ func parseJSONBool(data interface{}) (value bool, err error) {
 }
 
 func parseJSONFloat(data interface{}) (value float64, err error) {
-	if value, converted := data.(float64); converted {
-		return value, err
+	switch dataValue := data.(type) {
+	case float64:
+		return dataValue, nil
+	case json.Number:
+		value, err = strconv.ParseFloat(dataValue.String(), 64)
+		return value, convertJSONNumberError(err)
 	}
 	return value, ErrorIncorrectValueType
 }
@@ -194,16 +334,197 @@ func parseJSONInt(data interface{}) (value int64, err error) {
 		return int64(dataValue), nil
 	case int64:
 		return int64(dataValue), nil
+	case uint64:
+		if dataValue > math.MaxInt64 {
+			return value, ErrorValueOverflow
+		}
+		return int64(dataValue), nil
 	case float64:
-		// Check that value is integer.
-		if math.Abs(math.Trunc(dataValue)-dataValue) < math.Nextafter(0, 1) {
-			return int64(dataValue), nil
+		if !isJSONInteger(dataValue) {
+			return value, ErrorIncorrectValueType
 		}
-		return value, ErrorIncorrectValueType
+		if dataValue < math.MinInt64 || dataValue >= 1<<63 {
+			return value, ErrorValueOverflow
+		}
+		return int64(dataValue), nil
+	case json.Number:
+		if value, err = strconv.ParseInt(dataValue.String(), 10, 64); isJSONNumberParsed(err) {
+			return value, convertJSONNumberError(err)
+		}
+		return parseJSONInt(parseJSONNumberAsFloat(dataValue))
 	}
 	return value, ErrorIncorrectValueType
 }
 
+func parseJSONUint(data interface{}) (value uint64, err error) {
+	switch dataValue := data.(type) {
+	case int:
+		if dataValue < 0 {
+			return value, ErrorValueOverflow
+		}
+		return uint64(dataValue), nil
+	case int64:
+		if dataValue < 0 {
+			return value, ErrorValueOverflow
+		}
+		return uint64(dataValue), nil
+	case uint64:
+		return dataValue, nil
+	case float64:
+		if !isJSONInteger(dataValue) {
+			return value, ErrorIncorrectValueType
+		}
+		if dataValue < 0 || dataValue >= 1<<64 {
+			return value, ErrorValueOverflow
+		}
+		return uint64(dataValue), nil
+	case json.Number:
+		if value, err = strconv.ParseUint(dataValue.String(), 10, 64); isJSONNumberParsed(err) {
+			return value, convertJSONNumberError(err)
+		}
+		return parseJSONUint(parseJSONNumberAsFloat(dataValue))
+	}
+	return value, ErrorIncorrectValueType
+}
+
+// isJSONNumberParsed checks whether number is parsed as integer or it is integer that
+// overflows type. Other numbers (with fraction or exponent) are parsed as floats.
+func isJSONNumberParsed(err error) bool {
+	return err == nil || errors.Is(err, strconv.ErrRange)
+}
+
+func convertJSONNumberError(err error) error {
+	if err != nil {
+		return convertXMLNumberError(err)
+	}
+	return nil
+}
+
+// normalizeJSONNumbers replaces numbers in value (and in lists and objects of value) by
+// 'int64' (or 'uint64' if number overflows 'int64') for integer values and by 'float64' for
+// other values, so every number has single representation.
+func normalizeJSONNumbers(data interface{}) interface{} {
+	switch value := data.(type) {
+	case json.Number:
+		return normalizeJSONNumber(value)
+	case []interface{}:
+		normalized := make([]interface{}, len(value))
+		for i, element := range value {
+			normalized[i] = normalizeJSONNumbers(element)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			normalized[key] = normalizeJSONNumbers(element)
+		}
+		return normalized
+	}
+	return data
+}
+
+// normalizeJSONNumber returns integer value of number if it fits into 'int64' or 'uint64' and
+// float value otherwise. Integer values written as floats (like '2.0') are converted too.
+func normalizeJSONNumber(number json.Number) interface{} {
+	if value, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+		return value
+	}
+	if value, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+		return value
+	}
+	value, _ := number.Float64()
+	if isJSONInteger(value) && value >= math.MinInt64 && value < 1<<63 {
+		return int64(value)
+	}
+	if isJSONInteger(value) && value >= 0 && value < 1<<64 {
+		return uint64(value)
+	}
+	return value
+}
+
+// parseJSONNumberAsFloat returns float value of number or nil if number overflows float.
+func parseJSONNumberAsFloat(number json.Number) interface{} {
+	if value, err := number.Float64(); err == nil {
+		return value
+	}
+	return nil
+}
+
+// isJSONInteger checks that value is integer.
+func isJSONInteger(value float64) bool {
+	return math.Abs(math.Trunc(value)-value) < math.Nextafter(0, 1)
+}
+
+// Position helpers.
+func newJSONParseError(data []byte, err error) error {
+	parseError := &ParseError{Err: err}
+	if syntaxError, ok := err.(*json.SyntaxError); ok && syntaxError.Offset > 0 {
+		parseError.Position = newLineIndex(data).Position(int(syntaxError.Offset) - 1)
+	}
+	return parseError
+}
+
+// jsonPositionWalker collects positions of keys of objects using token stream of decoder.
+type jsonPositionWalker struct {
+	data      []byte
+	lines     lineIndex
+	decoder   *json.Decoder
+	positions positionIndex
+}
+
+func indexJSONPositions(data []byte) positionIndex {
+	walker := &jsonPositionWalker{data: data, lines: newLineIndex(data),
+		decoder: json.NewDecoder(bytes.NewReader(data)), positions: make(positionIndex)}
+	walker.positions[pathDelimiter] = walker.lines.Position(walker.nextOffset())
+	// Data is already parsed successfully, so walking error is impossible.
+	_ = walker.walk(pathDelimiter, true)
+	return walker.positions
+}
+
+// walk reads next value from decoder. Positions are recorded only for values that may be
+// addressed by path (values of arrays are not addressable).
+func (w *jsonPositionWalker) walk(path string, record bool) error {
+	token, err := w.decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		for w.decoder.More() {
+			offset := w.nextOffset()
+			key, err := w.decoder.Token()
+			if err != nil {
+				return err
+			}
+			keyPath := joinPath(path, key.(string))
+			if record {
+				w.positions[keyPath] = w.lines.Position(offset)
+			}
+			if err = w.walk(keyPath, record); err != nil {
+				return err
+			}
+		}
+		_, err = w.decoder.Token()
+	case json.Delim('['):
+		for w.decoder.More() {
+			if err = w.walk(path, false); err != nil {
+				return err
+			}
+		}
+		_, err = w.decoder.Token()
+	}
+	return err
+}
+
+// nextOffset returns offset of next token skipping spaces and separators.
+func (w *jsonPositionWalker) nextOffset() int {
+	offset := int(w.decoder.InputOffset())
+	for offset < len(w.data) && bytes.IndexByte([]byte(" \t\r\n,:"), w.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
 // Grabbing helpers.
 func createJSONValueGrabber(creator ValueSliceCreator, grabber ValueGrabber) ValueGrabber {
 	return func(element interface{}) (err error) {
@@ -214,7 +535,8 @@ func createJSONValueGrabber(creator ValueSliceCreator, grabber ValueGrabber) Val
 		creator(len(values))
 		for _, value := range values {
 			if err = grabber(value); err != nil {
-				return err
+				data, _ := parseJSONString(value)
+				return &PathError{Value: data, Err: err}
 			}
 		}
 		return nil
@@ -15,8 +15,10 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -156,7 +158,7 @@ func TestJsonGetValueEmptyPath(t *testing.T) {
 
 	for _, functors := range elementFunctors {
 		_, err = functors.Getter(config, "")
-		require.EqualError(t, err, ErrorNotFound.Error())
+		require.ErrorIs(t, err, ErrorNotFound)
 	}
 }
 
@@ -218,7 +220,7 @@ func TestJsonGrabAbsentValue(t *testing.T) {
 		return nil
 	})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -234,7 +236,7 @@ func TestJsonGrabAbsentValues(t *testing.T) {
 			return nil
 		})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -247,7 +249,7 @@ func TestJsonGrabValuePassError(t *testing.T) {
 		return expectedError
 	})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 func TestJsonGrabValuesPassError(t *testing.T) {
@@ -261,7 +263,7 @@ func TestJsonGrabValuesPassError(t *testing.T) {
 			return expectedError
 		})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 func TestJsonGrabValuesOfSingleElement(t *testing.T) {
@@ -274,7 +276,7 @@ func TestJsonGrabValuesOfSingleElement(t *testing.T) {
 			return nil
 		})
 
-	require.EqualError(t, err, ErrorIncorrectValueType.Error())
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
 }
 
 func TestJsonIncorrectInnerData(t *testing.T) {
@@ -282,7 +284,7 @@ func TestJsonIncorrectInnerData(t *testing.T) {
 
 	for element, functors := range elementFunctors {
 		_, err := functors.Getter(config, element)
-		require.EqualError(t, err, ErrorNotFound.Error())
+		require.ErrorIs(t, err, ErrorNotFound)
 	}
 }
 
@@ -351,6 +353,45 @@ func TestParseJsonInt(t *testing.T) {
 	require.EqualError(t, err, ErrorIncorrectValueType.Error())
 }
 
+func TestParseJsonUint(t *testing.T) {
+	value, err := parseJSONUint(expectedIntValue)
+	require.NoError(t, err, "Cannot parse json uint")
+	require.Equal(t, uint64(expectedIntValue), value)
+
+	value, err = parseJSONUint(uint64(math.MaxUint64))
+	require.NoError(t, err, "Cannot parse json uint")
+	require.Equal(t, uint64(math.MaxUint64), value)
+
+	value, err = parseJSONUint(float64(expectedIntValue))
+	require.NoError(t, err, "Cannot parse json uint")
+	require.Equal(t, uint64(expectedIntValue), value)
+
+	_, err = parseJSONUint(-expectedIntValue)
+	require.EqualError(t, err, ErrorValueOverflow.Error())
+
+	_, err = parseJSONUint(float64(1 << 64))
+	require.EqualError(t, err, ErrorValueOverflow.Error())
+
+	_, err = parseJSONUint(expectedStringValue)
+	require.EqualError(t, err, ErrorIncorrectValueType.Error())
+}
+
+func TestJsonGetUint(t *testing.T) {
+	config, err := newJSONConfig([]byte(oneLevelJSONConfig))
+	require.NoError(t, err, "Cannot parse json-config")
+
+	value, err := config.GetUint("/intElement")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(expectedIntValue), value)
+
+	values, err := config.GetUints("/intElements", defaultArrayDelimiter)
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, []uint64{123, 456, 789}, values)
+
+	_, err = config.GetUint("/stringElement")
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+}
+
 // Test GetConfigPart
 func TestJsonGetConfigPartRootFromRoot(t *testing.T) {
 	rootConfig, err := newJSONConfig([]byte(twoLevelJSONConfig))
@@ -372,7 +413,7 @@ func TestJsonGetConfigPartSectionFromRoot(t *testing.T) {
 	configPart, err := rootConfig.GetConfigPart("/root/child/grandchild/first")
 	require.NoError(t, err, "Cannot get config part")
 
-	require.Equal(t, configPart, expectedConfig, "Not equal configs")
+	require.Empty(t, Diff(expectedConfig, configPart), "Not equal configs")
 }
 
 func TestJsonGetConfigPartSectionFromSection(t *testing.T) {
@@ -396,10 +437,10 @@ func TestJsonGetConfigPartWithLongPath(t *testing.T) {
 	require.NoError(t, err, "Cannot get config section")
 
 	_, err = rootConfig.GetConfigPart("/root/child/grandchild/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 
 	_, err = configSection.GetConfigPart("/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 }
 
 func TestJsonGetAbsentConfigPart(t *testing.T) {
@@ -412,3 +453,113 @@ func TestJsonGetAbsentConfigPart(t *testing.T) {
 	_, err = config.GetConfigPart("/root/child/grandchild/third")
 	require.Error(t, err, ErrorNotFound.Error())
 }
+
+// Test loading of json.Unmarshaler values.
+type jsonUnmarshalerPoint struct {
+	X, Y int
+}
+
+func (p *jsonUnmarshalerPoint) UnmarshalJSON(data []byte) error {
+	var coordinates []int
+	if err := json.Unmarshal(data, &coordinates); err != nil {
+		return err
+	}
+	if len(coordinates) != 2 {
+		return errors.New("Point must contain two coordinates")
+	}
+	p.X, p.Y = coordinates[0], coordinates[1]
+	return nil
+}
+
+func TestJsonLoadUnmarshalerValue(t *testing.T) {
+	config, err := newJSONConfig([]byte(`{"point": [1, 2], "points": [[3, 4], [5, 6]],
+		"raw": {"key": "value"}, "incorrect": [1]}`))
+	require.NoError(t, err, "Cannot parse json-config")
+
+	var value struct {
+		Point  jsonUnmarshalerPoint   `config:"point"`
+		Points []jsonUnmarshalerPoint `config:"points"`
+		Raw    json.RawMessage        `config:"raw"`
+	}
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, jsonUnmarshalerPoint{X: 1, Y: 2}, value.Point)
+	require.Equal(t, []jsonUnmarshalerPoint{{X: 3, Y: 4}, {X: 5, Y: 6}}, value.Points)
+	require.JSONEq(t, `{"key": "value"}`, string(value.Raw))
+
+	var point jsonUnmarshalerPoint
+	err = LoadValue(config, "/incorrect", &point)
+	require.EqualError(t, err, "2:28: Point must contain two coordinates by path '/incorrect', "+
+		"expected type config.jsonUnmarshalerPoint")
+}
+
+func TestJsonLargeNumbers(t *testing.T) {
+	config, err := newJSONConfig([]byte(`{"id": 9007199254740993, "max": 18446744073709551615,
+		"overflow": 9223372036854775808, "float": 1.5, "exponent": 1e3, "huge": 1e400}`))
+	require.NoError(t, err, "Cannot parse json-config")
+
+	id, err := config.GetInt("/id")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, int64(9007199254740993), id)
+
+	maxValue, err := config.GetUint("/max")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(math.MaxUint64), maxValue)
+
+	_, err = config.GetInt("/overflow")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	_, err = config.GetInt("/float")
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
+
+	exponent, err := config.GetUint("/exponent")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(1000), exponent)
+
+	_, err = config.GetFloat("/huge")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	var ids []int64
+	config, err = newJSONConfig([]byte(`[9007199254740993, 9007199254740995]`))
+	require.NoError(t, err, "Cannot parse json-config")
+	require.NoError(t, LoadValue(config, "/", &ids))
+	require.Equal(t, []int64{9007199254740993, 9007199254740995}, ids)
+}
+
+func TestJsonSnowflakeIdentifiers(t *testing.T) {
+	config, err := newJSONConfig([]byte(`{"ids": {"user": 1541815603606036480, "chat": 1541815603606036481},
+		"limit": 1e21, "small": 2147483648}`))
+	require.NoError(t, err, "Cannot parse json-config")
+
+	var value struct {
+		IDs   map[string]interface{} `config:"-"`
+		User  int64                  `config:"ids/user"`
+		Chat  uint64                 `config:"ids/chat"`
+		Limit string                 `config:"limit"`
+		Tree  interface{}            `config:"ids"`
+	}
+	require.NoError(t, LoadValue(config, "/", &value))
+	require.Equal(t, int64(1541815603606036480), value.User)
+	require.Equal(t, uint64(1541815603606036481), value.Chat)
+	require.Equal(t, "1e21", value.Limit, "String must be original literal of number")
+	require.Equal(t, map[string]interface{}{"user": int64(1541815603606036480),
+		"chat": int64(1541815603606036481)}, value.Tree, "Integers must be kept exactly")
+
+	var small int32
+	err = LoadValue(config, "/small", &small)
+	require.EqualError(t, err, "2:18: Value overflows type by path '/small', value '2147483648', "+
+		"expected type int32")
+
+	err = config.GrabValue("/ids/user", func(data interface{}) error {
+		require.Equal(t, json.Number("1541815603606036480"), data)
+		return nil
+	})
+	require.NoError(t, err)
+
+	newConfig, err := newJSONConfig([]byte(`{"ids": {"user": 1541815603606036480, "chat": 1541815603606036482},
+		"limit": 1000000000000000000000, "small": 2147483648.0}`))
+	require.NoError(t, err, "Cannot parse json-config")
+	require.Equal(t, []Change{{Path: "/ids/chat", Type: ChangeModified,
+		OldValue: int64(1541815603606036481), NewValue: int64(1541815603606036482)}},
+		Diff(config, newConfig), "Only exact change of number must be found")
+}
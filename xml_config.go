@@ -23,81 +23,304 @@ import (
 	"unicode"
 )
 
+// InnerXMLPathPart is the last part of path to get raw inner XML of element, for example
+// '/page/template/#xml'.
+const InnerXMLPathPart = "#xml"
+
+// TextPathPart is key of text of element in tree of values if element also has attributes or
+// children (like '<port unit="tcp">80</port>'). It may be used as the last part of path too.
+const TextPathPart = "#text"
+
+// XMLSettings is settings of XML configs. By default elements and attributes are addressed
+// only by local names and namespaces are ignored, so path '/server' finds '<cfg:server>'.
+type XMLSettings struct {
+	// Namespaces binds prefixes of paths to namespace URIs, so path '/cfg:server/@cfg:port'
+	// addresses elements and attributes of namespace bound to prefix 'cfg' whatever prefix is
+	// used in document. Binding of empty prefix is applied to elements without prefix in path.
+	// Names without bound prefix are matched as they are written in document. Bindings
+	// enable qualified names.
+	Namespaces map[string]string
+	// Elements and attributes are addressed by names written in document (with prefixes).
+	QualifiedNames bool
+}
+
+// localNames checks whether elements and attributes are addressed only by local names.
+func (s XMLSettings) localNames() bool {
+	return !s.QualifiedNames && len(s.Namespaces) == 0
+}
+
+// CreateXMLConfig creates and parses XML config with specified settings.
+func CreateXMLConfig(configData []byte, settings XMLSettings) (Config, error) {
+	xmlRoot, err := parseXML(configData, settings.localNames())
+	if err != nil {
+		return nil, err
+	}
+	return &xmlConfig{data: xmlRoot, settings: settings}, nil
+}
+
 // Simple xml parsing.
 type xmlElement struct {
 	Attributes map[string]string
 	Value      string
 	Children   map[string][]*xmlElement
+	// Raw XML between start and end tags of element.
+	InnerXML string
+	// Namespace URI of element and its attributes.
+	Space           string
+	AttributeSpaces map[string]string
+
+	Position           Position
+	AttributePositions map[string]Position
 }
 
 func newXMLElement() *xmlElement {
 	return &xmlElement{
-		Attributes: make(map[string]string),
-		Value:      "",
-		Children:   make(map[string][]*xmlElement, 0)}
+		Attributes:         make(map[string]string),
+		Value:              "",
+		Children:           make(map[string][]*xmlElement, 0),
+		AttributeSpaces:    make(map[string]string),
+		AttributePositions: make(map[string]Position)}
 }
 
-func (e *xmlElement) SetAttributes(attibutes []xml.Attr) {
-	for _, attribute := range attibutes {
-		e.Attributes[attribute.Name.Local] = attribute.Value
-	}
+func (e *xmlElement) SetAttribute(name string, attribute xml.Attr) {
+	e.Attributes[name] = attribute.Value
+	e.AttributeSpaces[name] = attribute.Name.Space
 }
 
 func (e *xmlElement) AddChild(name string, child *xmlElement) {
 	e.Children[name] = append(e.Children[name], child)
 }
 
-func (e *xmlElement) SetValue(value string) {
-	value = strings.TrimFunc(value, unicode.IsSpace)
-	if len(value) > 0 {
-		e.Value = value
+// xmlTextChunk is text or CDATA section of element.
+type xmlTextChunk struct {
+	Text  string
+	CDATA bool
+}
+
+// SetText sets value of element from its text chunks joined in order. Whitespaces around value
+// are trimmed unless they are preserved or belong to CDATA sections.
+func (e *xmlElement) SetText(chunks []xmlTextChunk, preserve bool) {
+	if !preserve {
+		for len(chunks) > 0 && !chunks[0].CDATA {
+			if chunks[0].Text = strings.TrimLeftFunc(chunks[0].Text, unicode.IsSpace); len(chunks[0].Text) > 0 {
+				break
+			}
+			chunks = chunks[1:]
+		}
+		for last := len(chunks) - 1; last >= 0 && !chunks[last].CDATA; last-- {
+			if chunks[last].Text = strings.TrimRightFunc(chunks[last].Text, unicode.IsSpace); len(chunks[last].Text) > 0 {
+				break
+			}
+			chunks = chunks[:last]
+		}
 	}
+	var value strings.Builder
+	for _, chunk := range chunks {
+		value.WriteString(chunk.Text)
+	}
+	e.Value = value.String()
 }
 
-func parseXML(data []byte) (*xmlElement, error) {
+func (e *xmlElement) Walk(path string, visit valueVisitor) {
+	for name, value := range e.Attributes {
+		visit(joinPath(path, "@"+name), value)
+	}
+	if path != pathDelimiter && (len(e.Children) == 0 || len(e.Value) > 0) {
+		visit(path, e.Value)
+	}
+	for name, children := range e.Children {
+		if len(children) == 1 {
+			children[0].Walk(joinPath(path, name), visit)
+			continue
+		}
+		// Repeated elements are visited as list like lists of other configs.
+		list := make([]interface{}, len(children))
+		for i, child := range children {
+			list[i] = child.Tree()
+		}
+		visit(joinPath(path, name), list)
+	}
+}
+
+// Tree returns element as tree of values. Attributes are stored with prefix '@', text of
+// element is stored by key '#text', repeated children are stored as list.
+func (e *xmlElement) Tree() interface{} {
+	if len(e.Children) == 0 && len(e.Attributes) == 0 {
+		return e.Value
+	}
+	tree := make(map[string]interface{}, len(e.Attributes)+len(e.Children)+1)
+	for name, value := range e.Attributes {
+		tree["@"+name] = value
+	}
+	if len(e.Value) != 0 {
+		tree[TextPathPart] = e.Value
+	}
+	for name, children := range e.Children {
+		if len(children) == 1 {
+			tree[name] = children[0].Tree()
+			continue
+		}
+		list := make([]interface{}, len(children))
+		for i, child := range children {
+			list[i] = child.Tree()
+		}
+		tree[name] = list
+	}
+	return tree
+}
+
+// parseXML parses XML document. Elements and attributes are stored by names written in document
+// (with prefixes) or only by local names.
+func parseXML(data []byte, localNames bool) (*xmlElement, error) {
 	reader := bytes.NewReader(data)
 	decoder := xml.NewDecoder(reader)
-
+	// Inner XML of elements refers to the same source string.
+	source := string(data)
+
+	// xmlParsedElement is state of element that is parsed now.
+	type xmlParsedElement struct {
+		Element    *xmlElement
+		Chunks     []xmlTextChunk
+		Preserve   bool
+		InnerStart int64
+	}
 	xmlRoot := newXMLElement()
-	elements := []*xmlElement{xmlRoot}
-	lastElement := func() *xmlElement {
+	elements := []*xmlParsedElement{{Element: xmlRoot}}
+	lastElement := func() *xmlParsedElement {
 		return elements[len(elements)-1]
 	}
 
-	token, err := decoder.Token()
-	for ; err == nil; token, err = decoder.Token() {
+	lines := newLineIndex(data)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, newXMLParseError(lines, offset, err)
+		}
+
 		switch element := token.(type) {
 		case xml.StartElement:
 			newElement := newXMLElement()
-			newElement.SetAttributes(element.Attr)
-			lastElement().AddChild(element.Name.Local, newElement)
+			preserve := lastElement().Preserve
+			newElement.Space = element.Name.Space
+			newElement.Position = lines.Position(int(offset))
+			tag := data[offset:decoder.InputOffset()]
+			rawAttributes := findXMLAttributes(tag)
+			for i, attribute := range element.Attr {
+				name := attribute.Name.Local
+				if i < len(rawAttributes) {
+					if !localNames {
+						name = rawAttributes[i].Name
+					}
+					newElement.AttributePositions[name] = lines.Position(int(offset) + rawAttributes[i].Offset)
+				}
+				newElement.SetAttribute(name, attribute)
+				if isXMLSpaceAttribute(attribute.Name) {
+					preserve = attribute.Value == "preserve"
+				}
+			}
+			name := element.Name.Local
+			if !localNames {
+				name = getXMLTagName(tag)
+			}
+			lastElement().Element.AddChild(name, newElement)
 
-			elements = append(elements, newElement)
+			elements = append(elements, &xmlParsedElement{Element: newElement, Preserve: preserve,
+				InnerStart: decoder.InputOffset()})
 		case xml.EndElement:
+			parsed := lastElement()
+			parsed.Element.SetText(parsed.Chunks, parsed.Preserve)
+			parsed.Element.InnerXML = source[parsed.InnerStart:offset]
 			elements = elements[:len(elements)-1]
 		case xml.CharData:
-			lastElement().SetValue(string(element))
+			isCDATA := bytes.HasPrefix(data[offset:], []byte("<![CDATA["))
+			lastElement().Chunks = append(lastElement().Chunks, xmlTextChunk{Text: string(element), CDATA: isCDATA})
 		}
 	}
+	xmlRoot.SetText(elements[0].Chunks, false)
+	xmlRoot.InnerXML = source
 
-	if err != nil && err != io.EOF {
-		return nil, err
+	return xmlRoot, nil
+}
+
+// isXMLSpaceAttribute checks whether attribute is 'xml:space' that controls whitespaces.
+func isXMLSpaceAttribute(name xml.Name) bool {
+	return name.Local == "space" && (name.Space == "xml" || name.Space == "http://www.w3.org/XML/1998/namespace")
+}
+
+// xmlRawAttribute is name of attribute written in raw start tag and its offset in tag.
+type xmlRawAttribute struct {
+	Name   string
+	Offset int
+}
+
+// getXMLTagName returns name of element written in raw start tag.
+func getXMLTagName(tag []byte) string {
+	name := bytes.TrimPrefix(tag, []byte("<"))
+	if end := bytes.IndexAny(name, " \t\r\n/>"); end >= 0 {
+		name = name[:end]
 	}
+	return string(name)
+}
 
-	return xmlRoot, nil
+// findXMLAttributes returns attributes of raw start tag in order of their appearance.
+func findXMLAttributes(tag []byte) []xmlRawAttribute {
+	isSpace := func(symbol byte) bool {
+		return strings.IndexByte(" \t\r\n", symbol) >= 0
+	}
+	attributes := make([]xmlRawAttribute, 0)
+	offset := bytes.IndexAny(tag, " \t\r\n")
+	for offset >= 0 && offset < len(tag) {
+		for offset < len(tag) && isSpace(tag[offset]) {
+			offset++
+		}
+		start := offset
+		for offset < len(tag) && !isSpace(tag[offset]) && bytes.IndexByte([]byte("=/>"), tag[offset]) < 0 {
+			offset++
+		}
+		if offset == start {
+			break
+		}
+		attributes = append(attributes, xmlRawAttribute{Name: string(tag[start:offset]), Offset: start})
+
+		quote := bytes.IndexAny(tag[offset:], "\"'")
+		if quote < 0 {
+			break
+		}
+		offset += quote
+		end := bytes.IndexByte(tag[offset+1:], tag[offset])
+		if end < 0 {
+			break
+		}
+		offset += end + 2
+	}
+	return attributes
+}
+
+func newXMLParseError(lines lineIndex, offset int64, err error) error {
+	parseError := &ParseError{Err: err}
+	if syntaxError, ok := err.(*xml.SyntaxError); ok {
+		parseError.Position = lines.Position(int(offset))
+		if parseError.Position.Line != syntaxError.Line {
+			parseError.Position = Position{Line: syntaxError.Line}
+		}
+	}
+	return parseError
 }
 
 // Xml config implementation.
 type xmlConfig struct {
-	data *xmlElement
+	data     *xmlElement
+	file     string
+	settings XMLSettings
+	// Flag that specifies whether to match names after normalization.
+	normalizeKeys bool
 }
 
 func newXMLConfig(data []byte) (Config, error) {
-	xmlRoot, err := parseXML(data)
-	if err != nil {
-		return nil, err
-	}
-	return &xmlConfig{data: xmlRoot}, nil
+	return CreateXMLConfig(data, XMLSettings{})
 }
 
 // Grabbers.
@@ -115,7 +338,7 @@ func (c *xmlConfig) GrabValues(path string, delim string,
 func (c *xmlConfig) GetString(path string) (value string, err error) {
 	element, attribute, err := c.findElement(path)
 	if err != nil {
-		return "", err
+		return "", setPathErrorType(wrapPathError(c, path, "", err), "string")
 	}
 	if element != nil {
 		return element.Value, nil
@@ -124,40 +347,47 @@ func (c *xmlConfig) GetString(path string) (value string, err error) {
 }
 
 func (c *xmlConfig) GetBool(path string) (value bool, err error) {
-	return value, GrabStringValue(c, path, func(data string) error {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
 		value, err = parseXMLBool(data)
 		return err
-	})
+	}), "bool")
 }
 
 func (c *xmlConfig) GetFloat(path string) (value float64, err error) {
-	return value, GrabStringValue(c, path, func(data string) error {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
 		value, err = parseXMLFloat(data)
 		return err
-	})
+	}), "float64")
 }
 
 func (c *xmlConfig) GetInt(path string) (value int64, err error) {
-	return value, GrabStringValue(c, path, func(data string) error {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
 		value, err = parseXMLInt(data)
 		return err
-	})
+	}), "int64")
+}
+
+func (c *xmlConfig) GetUint(path string) (value uint64, err error) {
+	return value, setPathErrorType(GrabStringValue(c, path, func(data string) error {
+		value, err = parseXMLUint(data)
+		return err
+	}), "uint64")
 }
 
 // Get array of values.
 func (c *xmlConfig) GetStrings(path string, delim string) (value []string, err error) {
 	stringValue, err := c.GetString(path)
 	if err != nil {
-		return value, err
+		return value, setPathErrorType(err, "[]string")
 	}
-	if len(stringValue) == 0 {
-		return make([]string, 0), nil
+	if value, err = SplitList(stringValue, delim, defaultListSettings); err != nil {
+		return value, setPathErrorType(wrapPathError(c, path, stringValue, err), "[]string")
 	}
-	return strings.Split(stringValue, delim), nil
+	return value, nil
 }
 
 func (c *xmlConfig) GetBools(path string, delim string) (value []bool, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]bool, 0, cap) },
 		func(data string) error {
 			var parsed bool
@@ -165,11 +395,11 @@ func (c *xmlConfig) GetBools(path string, delim string) (value []bool, err error
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]bool")
 }
 
 func (c *xmlConfig) GetFloats(path string, delim string) (value []float64, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]float64, 0, cap) },
 		func(data string) error {
 			var parsed float64
@@ -177,11 +407,11 @@ func (c *xmlConfig) GetFloats(path string, delim string) (value []float64, err e
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]float64")
 }
 
 func (c *xmlConfig) GetInts(path string, delim string) (value []int64, err error) {
-	return value, GrabStringValues(c, path, delim,
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
 		func(cap int) { value = make([]int64, 0, cap) },
 		func(data string) error {
 			var parsed int64
@@ -189,7 +419,23 @@ func (c *xmlConfig) GetInts(path string, delim string) (value []int64, err error
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]int64")
+}
+
+func (c *xmlConfig) GetUints(path string, delim string) (value []uint64, err error) {
+	return value, setPathErrorType(GrabStringValues(c, path, delim,
+		func(cap int) { value = make([]uint64, 0, cap) },
+		func(data string) error {
+			var parsed uint64
+			if parsed, err = parseXMLUint(data); err == nil {
+				value = append(value, parsed)
+			}
+			return err
+		}), "[]uint64")
+}
+
+func (c *xmlConfig) GetIntRanges(path string, delim string) ([]int64, error) {
+	return getIntRanges(c, path, delim)
 }
 
 // Get subconfig.
@@ -198,45 +444,175 @@ func (c *xmlConfig) GetConfigPart(path string) (Config, error) {
 		return c, nil
 	}
 	element, _, err := c.findElement(path)
+	if err == nil && element == nil {
+		err = ErrorNotFound
+	}
 	if err != nil {
-		return nil, err
+		return nil, wrapPathError(c, path, "", err)
 	}
-	if element == nil {
-		return nil, ErrorNotFound
+	return &xmlConfig{data: element, file: c.file, settings: c.settings, normalizeKeys: c.normalizeKeys}, nil
+}
+
+// Get position of value.
+func (c *xmlConfig) Position(path string) (Position, error) {
+	element := c.data
+	position, exist := element.Position, element.Position.IsValid()
+	for _, pathPart := range splitPath(path) {
+		if pathPart == InnerXMLPathPart || pathPart == TextPathPart {
+			break
+		} else if strings.HasPrefix(pathPart, "@") {
+			name, err := c.findAttributeName(element, pathPart[1:])
+			position, exist = element.AttributePositions[name]
+			exist = exist && err == nil
+			break
+		}
+		children, err := c.findChildren(element, pathPart)
+		if err != nil {
+			exist = false
+			break
+		}
+		element = children[0]
+		position, exist = element.Position, true
+	}
+	if !exist {
+		return Position{}, &PathError{Path: joinPath(path), Err: ErrorNotFound}
 	}
-	return &xmlConfig{data: element}, nil
+	position.File = c.file
+	return position, nil
 }
 
 // Xml helpers.
+func (c *xmlConfig) sourceFile() string {
+	return c.file
+}
+
+func (c *xmlConfig) setSourceFile(file string) {
+	c.file = file
+}
+
+func (c *xmlConfig) valueTree() interface{} {
+	return c.data.Tree()
+}
+
+func (c *xmlConfig) walkValues(visit valueVisitor) {
+	c.data.Walk(pathDelimiter, visit)
+}
+
 func (c *xmlConfig) findElement(path string) (*xmlElement, string, error) {
 	element := c.data
 	pathParts := splitPath(path)
-	if len(pathParts) == 0 {
+	if len(element.Children) != 0 && len(pathParts) == 0 {
+		// Only elements without children may be addressed by empty path.
 		return nil, "", ErrorNotFound
 	}
 	for _, pathPart := range pathParts {
-		if strings.HasPrefix(pathPart, "@") {
-			if attribute, exist := element.Attributes[pathPart[1:]]; exist {
-				return nil, attribute, nil
+		if pathPart == InnerXMLPathPart {
+			return nil, element.InnerXML, nil
+		} else if pathPart == TextPathPart {
+			return nil, element.Value, nil
+		} else if strings.HasPrefix(pathPart, "@") {
+			name, err := c.findAttributeName(element, pathPart[1:])
+			if err != nil {
+				return nil, "", err
 			}
-			return nil, "", ErrorNotFound
+			return nil, element.Attributes[name], nil
 		}
-		if part, ok := element.Children[pathPart]; ok {
-			element = part[0]
-		} else {
-			return nil, "", ErrorNotFound
+		children, err := c.findChildren(element, pathPart)
+		if err != nil {
+			return nil, "", err
 		}
+		element = children[0]
 	}
 	return element, "", nil
 }
 
+// findChildren returns children of element by name of path. Names with prefix bound by settings
+// are matched by namespace URI and local name, other names are matched as they are written.
+func (c *xmlConfig) findChildren(element *xmlElement, name string) ([]*xmlElement, error) {
+	space, local, bound := c.resolveName(name, true)
+	if !bound {
+		name, err := findXMLName(element.Children, name, c.normalizeKeys)
+		if err != nil {
+			return nil, err
+		}
+		return element.Children[name], nil
+	}
+	var result []*xmlElement
+	for _, childName := range sortedKeys(element.Children) {
+		for _, child := range element.Children[childName] {
+			if child.Space == space && c.isSameName(getXMLLocalName(childName), local) {
+				result = append(result, child)
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil, ErrorNotFound
+	}
+	return result, nil
+}
+
+// findAttributeName returns name of element attribute by name of path. Attributes without prefix
+// do not belong to any namespace, so only prefixed names are resolved by settings.
+func (c *xmlConfig) findAttributeName(element *xmlElement, name string) (string, error) {
+	space, local, bound := c.resolveName(name, false)
+	if !bound {
+		return findXMLName(element.Attributes, name, c.normalizeKeys)
+	}
+	for _, attributeName := range sortedKeys(element.Attributes) {
+		if element.AttributeSpaces[attributeName] == space && c.isSameName(getXMLLocalName(attributeName), local) {
+			return attributeName, nil
+		}
+	}
+	return "", ErrorNotFound
+}
+
+// findXMLName returns name written in config that matches name of path.
+func findXMLName[T any](values map[string]T, name string, normalizeKeys bool) (string, error) {
+	if normalizeKeys {
+		return findNormalizedKey(sortedKeys(values), name)
+	}
+	if _, exist := values[name]; !exist {
+		return "", ErrorNotFound
+	}
+	return name, nil
+}
+
+func (c *xmlConfig) isSameName(name string, otherName string) bool {
+	if c.normalizeKeys {
+		return NormalizeKey(name) == NormalizeKey(otherName)
+	}
+	return name == otherName
+}
+
+func (c *xmlConfig) withNormalizedKeys() Config {
+	config := *c
+	config.normalizeKeys = true
+	return &config
+}
+
+// resolveName returns namespace URI and local name of path name if its prefix is bound by
+// settings.
+func (c *xmlConfig) resolveName(name string, isElement bool) (space string, local string, bound bool) {
+	if c.settings.localNames() {
+		return "", name, false
+	}
+	prefix := ""
+	if separator := strings.Index(name, ":"); separator >= 0 {
+		prefix, name = name[:separator], name[separator+1:]
+	} else if !isElement {
+		return "", name, false
+	}
+	space, bound = c.settings.Namespaces[prefix]
+	return space, name, bound
+}
+
+func getXMLLocalName(name string) string {
+	return name[strings.Index(name, ":")+1:]
+}
+
 // Xml value parsers.
 func parseXMLBool(data string) (value bool, err error) {
-	value, err = strconv.ParseBool(data)
-	if err != nil {
-		return false, ErrorIncorrectValueType
-	}
-	return value, nil
+	return ParseBool(data, defaultBoolVocabulary)
 }
 
 func parseXMLFloat(data string) (value float64, err error) {
@@ -248,13 +624,45 @@ func parseXMLFloat(data string) (value float64, err error) {
 }
 
 func parseXMLInt(data string) (value int64, err error) {
-	value, err = strconv.ParseInt(data, 10, 64)
+	value, err = strconv.ParseInt(data, getIntegerBase(data), 64)
 	if err != nil {
-		return 0, ErrorIncorrectValueType
+		return 0, convertXMLNumberError(err)
 	}
 	return value, nil
 }
 
+func parseXMLUint(data string) (value uint64, err error) {
+	value, err = strconv.ParseUint(data, getIntegerBase(data), 64)
+	if err != nil {
+		if strings.HasPrefix(data, "-") {
+			if _, signedErr := strconv.ParseInt(data, getIntegerBase(data), 64); signedErr == nil {
+				return 0, ErrorValueOverflow
+			}
+		}
+		return 0, convertXMLNumberError(err)
+	}
+	return value, nil
+}
+
+// getIntegerBase returns base to parse integer. Integers may have prefixes '0x', '0o' and '0b'
+// and underscores between digits, but integers with leading zeros are decimal.
+func getIntegerBase(data string) int {
+	digits := strings.TrimLeft(data, "+-")
+	if len(digits) > 1 && digits[0] == '0' && strings.ContainsRune("xXoObB", rune(digits[1])) {
+		return 0
+	} else if len(digits) > 0 && digits[0] != '0' && strings.Contains(digits, "_") {
+		return 0
+	}
+	return 10
+}
+
+func convertXMLNumberError(err error) error {
+	if numError, ok := err.(*strconv.NumError); ok && numError.Err == strconv.ErrRange {
+		return ErrorValueOverflow
+	}
+	return ErrorIncorrectValueType
+}
+
 // Grabbing helpers.
 func createXMLValueGrabber(grabber ValueGrabber) StringValueGrabber {
 	return func(data string) error {
@@ -17,7 +17,9 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -197,7 +199,7 @@ func TestXmlGetValueEmptyPath(t *testing.T) {
 
 	for _, functors := range elementFunctors {
 		_, err = functors.Getter(config, "")
-		require.EqualError(t, err, ErrorNotFound.Error())
+		require.ErrorIs(t, err, ErrorNotFound)
 	}
 }
 
@@ -269,7 +271,7 @@ func TestXmlGrabAbsentValue(t *testing.T) {
 		return nil
 	})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -285,7 +287,7 @@ func TestXmlGrabAbsentValues(t *testing.T) {
 			return nil
 		})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -298,7 +300,7 @@ func TestXmlGrabValuePassError(t *testing.T) {
 		return expectedError
 	})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 func TestXmlGrabValuesPassError(t *testing.T) {
@@ -312,7 +314,7 @@ func TestXmlGrabValuesPassError(t *testing.T) {
 			return expectedError
 		})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 // Parser tests.
@@ -350,6 +352,85 @@ func TestParseXmlInt(t *testing.T) {
 	require.EqualError(t, err, ErrorIncorrectValueType.Error())
 }
 
+func TestParseXmlUint(t *testing.T) {
+	value, err := parseXMLUint(fmt.Sprint(expectedIntValue))
+	require.NoError(t, err, "Cannot parse xml uint")
+	require.Equal(t, uint64(expectedIntValue), value)
+
+	value, err = parseXMLUint("18446744073709551615")
+	require.NoError(t, err, "Cannot parse xml uint")
+	require.Equal(t, uint64(math.MaxUint64), value)
+
+	_, err = parseXMLUint("18446744073709551616")
+	require.EqualError(t, err, ErrorValueOverflow.Error())
+
+	_, err = parseXMLUint("-1")
+	require.EqualError(t, err, ErrorValueOverflow.Error())
+
+	_, err = parseXMLUint(expectedStringValue)
+	require.EqualError(t, err, ErrorIncorrectValueType.Error())
+
+	_, err = parseXMLInt("9223372036854775808")
+	require.EqualError(t, err, ErrorValueOverflow.Error())
+}
+
+func TestParseXmlIntWithBasePrefixes(t *testing.T) {
+	for data, expected := range map[string]int64{
+		"0x1F": 31, "-0X1f": -31, "0o755": 493, "0b101": 5, "1_000_000": 1000000,
+		"0x_FF": 255, "0755": 755,
+	} {
+		value, err := parseXMLInt(data)
+		require.NoError(t, err, data)
+		require.Equal(t, expected, value, data)
+
+		if expected >= 0 {
+			unsignedValue, err := parseXMLUint(data)
+			require.NoError(t, err, data)
+			require.Equal(t, uint64(expected), unsignedValue, data)
+		}
+	}
+
+	for _, data := range []string{"0x", "1__000", "_1", "0_755", "0b102", "0o8"} {
+		_, err := parseXMLInt(data)
+		require.ErrorIs(t, err, ErrorIncorrectValueType, data)
+	}
+	_, err := parseXMLUint("-0x1")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+	_, err = parseXMLInt("0x8000000000000000")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+}
+
+func TestXmlGetIntsWithBasePrefixes(t *testing.T) {
+	config, err := newXMLConfig([]byte("<mode>0o644</mode><masks>0xFF 0b11 1_024</masks>" +
+		"<ports>0x1F40-0x1F42</ports>"))
+	require.NoError(t, err, "Cannot parse xml-config")
+
+	mode, err := config.GetUint("/mode")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(0644), mode)
+
+	masks, err := config.GetInts("/masks", defaultArrayDelimiter)
+	require.NoError(t, err, "Cannot get values")
+	require.Equal(t, []int64{255, 3, 1024}, masks)
+
+	var ports []uint16
+	require.NoError(t, LoadValue(config, "/ports", &ports))
+	require.Equal(t, []uint16{8000, 8001, 8002}, ports)
+}
+
+func TestXmlGetUint(t *testing.T) {
+	config, err := newXMLConfig([]byte(oneLevelXMLConfig))
+	require.NoError(t, err, "Cannot parse xml-config")
+
+	value, err := config.GetUint("/xml/intElement")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(expectedIntValue), value)
+
+	values, err := config.GetUints("/xml/intElements", defaultArrayDelimiter)
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, []uint64{123, 456, 789}, values)
+}
+
 // Test GetConfigPart
 func TestXmlGetConfigPartRootFromRoot(t *testing.T) {
 	rootConfig, err := newXMLConfig([]byte(twoLevelXMLConfig))
@@ -374,7 +455,7 @@ func TestXmlGetConfigPartSectionFromRoot(t *testing.T) {
 	configPart, err := rootConfig.GetConfigPart("/xml/root/child/grandchild/first")
 	require.NoError(t, err, "Cannot get config part")
 
-	require.Equal(t, expectedConfigPart, configPart, "Not equal configs")
+	require.Empty(t, Diff(expectedConfigPart, configPart), "Not equal configs")
 }
 
 func TestXmlGetConfigPartSectionFromSection(t *testing.T) {
@@ -398,10 +479,10 @@ func TestXmlGetConfigPartWithLongPath(t *testing.T) {
 	require.NoError(t, err, "Cannot get config section")
 
 	_, err = rootConfig.GetConfigPart("/xml/root/child/grandchild/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 
 	_, err = configSection.GetConfigPart("/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 }
 
 func TestXmlGetAbsentConfigPart(t *testing.T) {
@@ -414,3 +495,130 @@ func TestXmlGetAbsentConfigPart(t *testing.T) {
 	_, err = config.GetConfigPart("/xml/@element")
 	require.Error(t, err, ErrorNotFound.Error())
 }
+
+func TestXmlNamespaces(t *testing.T) {
+	data := `<beans xmlns="urn:beans" xmlns:a="urn:cache" xmlns:b="urn:db">
+		<a:timeout>5s</a:timeout><b:timeout>30s</b:timeout>
+		<b:server b:port="5432" port="80"><b:host>db1</b:host></b:server></beans>`
+
+	config, err := CreateXMLConfig([]byte(data), XMLSettings{QualifiedNames: true})
+	require.NoError(t, err, "Cannot parse xml-config")
+	for path, expected := range map[string]string{
+		"/beans/a:timeout":        "5s",
+		"/beans/b:timeout":        "30s",
+		"/beans/b:server/@b:port": "5432",
+		"/beans/b:server/@port":   "80",
+		"/beans/b:server/b:host":  "db1",
+		"/beans/@xmlns:a":         "urn:cache",
+	} {
+		value, err := config.GetString(path)
+		require.NoError(t, err, path)
+		require.Equal(t, expected, value, path)
+	}
+	_, err = config.GetString("/beans/timeout")
+	require.ErrorIs(t, err, ErrorNotFound)
+
+	settings := XMLSettings{Namespaces: map[string]string{"": "urn:beans", "cfg": "urn:db"}}
+	config, err = CreateXMLConfig([]byte(data), settings)
+	require.NoError(t, err, "Cannot parse xml-config")
+	duration, err := GetDuration(config, "/beans/cfg:timeout")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, duration)
+	port, err := config.GetInt("/beans/cfg:server/@cfg:port")
+	require.NoError(t, err)
+	require.Equal(t, int64(5432), port)
+	position, err := config.Position("/beans/cfg:server/@cfg:port")
+	require.NoError(t, err)
+	require.Equal(t, Position{Line: 3, Column: 13}, position)
+
+	part, err := config.GetConfigPart("/beans/cfg:server")
+	require.NoError(t, err)
+	host, err := part.GetString("/cfg:host")
+	require.NoError(t, err, "Prefix bindings must be kept in config part")
+	require.Equal(t, "db1", host)
+	_, err = part.GetString("/host")
+	require.ErrorIs(t, err, ErrorNotFound, "Element without prefix must be in bound default namespace")
+
+	config, err = CreateConfigFromString(data, XML)
+	require.NoError(t, err, "Cannot parse xml-config")
+	host, err = config.GetString("/beans/server/host")
+	require.NoError(t, err, "Local names must be used by default")
+	require.Equal(t, "db1", host)
+}
+
+func TestXmlLocalNamesByDefault(t *testing.T) {
+	config, err := newXMLConfig([]byte(`<cfg:server xmlns:cfg="urn:db" cfg:port="5432">db1</cfg:server>`))
+	require.NoError(t, err, "Cannot parse xml-config")
+
+	server, err := config.GetString("/server")
+	require.NoError(t, err)
+	require.Equal(t, "db1", server)
+	port, err := config.GetInt("/server/@port")
+	require.NoError(t, err)
+	require.Equal(t, int64(5432), port)
+	_, err = config.GetString("/cfg:server")
+	require.ErrorIs(t, err, ErrorNotFound, "Prefixed names must be used only if they are enabled")
+}
+
+func TestXmlMixedContent(t *testing.T) {
+	config, err := newXMLConfig([]byte(`<page>
+		<title>  Hello, <!-- greeting --> world!  </title>
+		<note>first <b>bold</b> second</note>
+		<script><![CDATA[
+    if (a < b) {
+        run();
+    }
+]]></script>
+		<code>  <![CDATA[ x ]]>  </code>
+		<pre xml:space="preserve">  keep  <line> spaces </line></pre>
+		<template><div class="row">{{.Name}}</div></template>
+	</page>`))
+	require.NoError(t, err, "Cannot parse xml-config")
+
+	for path, expected := range map[string]string{
+		"/page/title":          "Hello,  world!",
+		"/page/note":           "first  second",
+		"/page/script":         "\n    if (a < b) {\n        run();\n    }\n",
+		"/page/code":           " x ",
+		"/page/pre":            "  keep  ",
+		"/page/pre/line":       " spaces ",
+		"/page/template/#xml":  `<div class="row">{{.Name}}</div>`,
+		"/page/template/div":   "{{.Name}}",
+		"/page/note/b/#xml":    "bold",
+		"/page/template/@none": "",
+	} {
+		value, err := config.GetString(path)
+		if len(expected) == 0 {
+			require.ErrorIs(t, err, ErrorNotFound, path)
+			continue
+		}
+		require.NoError(t, err, path)
+		require.Equal(t, expected, value, path)
+	}
+
+	part, err := config.GetConfigPart("/page/template")
+	require.NoError(t, err)
+	value, err := part.GetString("/" + InnerXMLPathPart)
+	require.NoError(t, err)
+	require.Equal(t, `<div class="row">{{.Name}}</div>`, value)
+
+	position, err := config.Position("/page/template/#xml")
+	require.NoError(t, err)
+	require.Equal(t, Position{Line: 11, Column: 3}, position)
+}
+
+func TestXmlTreeKeepsText(t *testing.T) {
+	config, err := newXMLConfig([]byte(`<server><port unit="tcp">80</port><name>api<alias>web</alias></name></server>`))
+	require.NoError(t, err, "Cannot parse xml-config")
+
+	var value interface{}
+	require.NoError(t, LoadValue(config, "/server", &value))
+	require.Equal(t, map[string]interface{}{
+		"port": map[string]interface{}{"@unit": "tcp", TextPathPart: "80"},
+		"name": map[string]interface{}{"alias": "web", TextPathPart: "api"},
+	}, value)
+
+	port, err := config.GetInt("/server/port/#text")
+	require.NoError(t, err)
+	require.Equal(t, int64(80), port)
+}
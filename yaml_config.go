@@ -15,17 +15,36 @@
 package config
 
 import (
-	yaml "gopkg.in/yaml.v2"
+	"fmt"
+	"reflect"
+	"sort"
+
+	yaml "gopkg.in/yaml.v3"
 )
 
 type yamlConfig struct {
-	data interface{}
+	data      interface{}
+	file      string
+	positions positionIndex
+	prefix    string
+	// Flag that specifies whether to match keys after normalization.
+	normalizeKeys bool
 }
 
 func newYAMLConfig(data []byte) (Config, error) {
-	var config yamlConfig
-
-	if err := yaml.Unmarshal(data, &config.data); err != nil {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, newYAMLParseError(data, err)
+	}
+	config := yamlConfig{positions: make(positionIndex)}
+	if len(document.Content) == 0 {
+		return &config, nil
+	}
+	root := document.Content[0]
+	config.positions[pathDelimiter] = Position{Line: root.Line, Column: root.Column}
+	decoder := yamlDecoder{positions: config.positions}
+	var err error
+	if config.data, err = decoder.decode(root, pathDelimiter, true, true); err != nil {
 		return nil, err
 	}
 	return &config, nil
@@ -35,9 +54,10 @@ func newYAMLConfig(data []byte) (Config, error) {
 func (c *yamlConfig) GrabValue(path string, grabber ValueGrabber) (err error) {
 	element, err := c.findElement(path)
 	if err != nil {
-		return err
+		return wrapPathError(c, path, "", err)
 	}
-	return grabber(element)
+	value, _ := parseJSONString(element)
+	return wrapPathError(c, path, value, grabber(element))
 }
 
 func (c *yamlConfig) GrabValues(path string, delim string,
@@ -48,36 +68,43 @@ func (c *yamlConfig) GrabValues(path string, delim string,
 
 // Get single value.
 func (c *yamlConfig) GetString(path string) (value string, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseYAMLString(data)
 		return err
-	})
+	}), "string")
 }
 
 func (c *yamlConfig) GetBool(path string) (value bool, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseYAMLBool(data)
 		return err
-	})
+	}), "bool")
 }
 
 func (c *yamlConfig) GetFloat(path string) (value float64, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseYAMLFloat(data)
 		return err
-	})
+	}), "float64")
 }
 
 func (c *yamlConfig) GetInt(path string) (value int64, err error) {
-	return value, c.GrabValue(path, func(data interface{}) error {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
 		value, err = parseYAMLInt(data)
 		return err
-	})
+	}), "int64")
+}
+
+func (c *yamlConfig) GetUint(path string) (value uint64, err error) {
+	return value, setPathErrorType(c.GrabValue(path, func(data interface{}) error {
+		value, err = parseYAMLUint(data)
+		return err
+	}), "uint64")
 }
 
 // Get array of values.
 func (c *yamlConfig) GetStrings(path string, delim string) (value []string, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]string, 0, cap) },
 		func(data interface{}) error {
 			var parsed string
@@ -85,11 +112,11 @@ func (c *yamlConfig) GetStrings(path string, delim string) (value []string, err
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]string")
 }
 
 func (c *yamlConfig) GetBools(path string, delim string) (value []bool, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]bool, 0, cap) },
 		func(data interface{}) error {
 			var parsed bool
@@ -97,11 +124,11 @@ func (c *yamlConfig) GetBools(path string, delim string) (value []bool, err erro
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]bool")
 }
 
 func (c *yamlConfig) GetFloats(path string, delim string) (value []float64, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]float64, 0, cap) },
 		func(data interface{}) error {
 			var parsed float64
@@ -109,11 +136,11 @@ func (c *yamlConfig) GetFloats(path string, delim string) (value []float64, err
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]float64")
 }
 
 func (c *yamlConfig) GetInts(path string, delim string) (value []int64, err error) {
-	return value, c.GrabValues(path, delim,
+	return value, setPathErrorType(c.GrabValues(path, delim,
 		func(cap int) { value = make([]int64, 0, cap) },
 		func(data interface{}) error {
 			var parsed int64
@@ -121,7 +148,23 @@ func (c *yamlConfig) GetInts(path string, delim string) (value []int64, err erro
 				value = append(value, parsed)
 			}
 			return err
-		})
+		}), "[]int64")
+}
+
+func (c *yamlConfig) GetUints(path string, delim string) (value []uint64, err error) {
+	return value, setPathErrorType(c.GrabValues(path, delim,
+		func(cap int) { value = make([]uint64, 0, cap) },
+		func(data interface{}) error {
+			var parsed uint64
+			if parsed, err = parseYAMLUint(data); err == nil {
+				value = append(value, parsed)
+			}
+			return err
+		}), "[]uint64")
+}
+
+func (c *yamlConfig) GetIntRanges(path string, delim string) ([]int64, error) {
+	return getIntRanges(c, path, delim)
 }
 
 // Get subconfig.
@@ -131,29 +174,296 @@ func (c *yamlConfig) GetConfigPart(path string) (Config, error) {
 	}
 	element, err := c.findElement(path)
 	if err != nil {
-		return nil, err
+		return nil, wrapPathError(c, path, "", err)
 	}
-	return &yamlConfig{data: element}, nil
+	return &yamlConfig{data: element, file: c.file, positions: c.positions,
+		prefix: concatPaths(c.prefix, c.resolvePath(path)), normalizeKeys: c.normalizeKeys}, nil
+}
+
+// Get position of value.
+func (c *yamlConfig) Position(path string) (Position, error) {
+	return findPosition(c, c.positions, c.prefix, c.resolvePath(path))
 }
 
 // Yaml helpers.
+func (c *yamlConfig) sourceFile() string {
+	return c.file
+}
+
+func (c *yamlConfig) setSourceFile(file string) {
+	c.file = file
+}
+
+// yamlDecoder converts node tree into tree of maps ('map[interface{}]interface{}'), lists and
+// scalar values and records positions of keys of mappings in the same walk, so positions
+// always point to nodes of values.
+type yamlDecoder struct {
+	positions positionIndex
+	// Counters of decoded nodes used to limit expansion of aliases like 'yaml.v3' does.
+	decoded int
+	aliased int
+	// Depth of aliases which content is decoded.
+	aliases int
+}
+
+// decode converts node. Positions are recorded only for values that may be addressed by path
+// (values of lists are not addressable). Positions of merged or aliased keys do not override
+// positions of explicit keys.
+func (d *yamlDecoder) decode(node *yaml.Node, path string, record bool, override bool) (interface{}, error) {
+	d.decoded++
+	if d.aliases > 0 {
+		d.aliased++
+	}
+	if d.aliased > 100 && d.decoded > 1000 &&
+		float64(d.aliased)/float64(d.decoded) > allowedYAMLAliasRatio(d.decoded) {
+		return nil, newYAMLNodeError(node, "document contains excessive aliasing")
+	}
+	switch node.Kind {
+	case yaml.AliasNode:
+		d.aliases++
+		defer func() { d.aliases-- }()
+		return d.decode(node.Alias, path, record, false)
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := d.decode(item, path, false, false)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case yaml.MappingNode:
+		return d.decodeMapping(node, path, record, override)
+	}
+	return decodeYAMLScalar(node)
+}
+
+// decodeYAMLScalar decodes scalar value. Plain YAML 1.1 bools (like 'on' or 'yes') are decoded
+// as bools and timestamps are kept as strings, as it was done by 'yaml.v2'.
+func decodeYAMLScalar(node *yaml.Node) (interface{}, error) {
+	plain := node.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|
+		yaml.LiteralStyle|yaml.FoldedStyle) == 0
+	if value, isBool := yaml11Bools[node.Value]; plain && isBool {
+		return value, nil
+	} else if node.ShortTag() == "!!timestamp" {
+		return node.Value, nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, &ParseError{Position: Position{Line: node.Line, Column: node.Column}, Err: err}
+	}
+	return value, nil
+}
+
+// decodeMapping converts mapping node into map. Keys merged from other mappings do not
+// override explicit keys, keys of the first merged mappings override keys of next ones.
+func (d *yamlDecoder) decodeMapping(node *yaml.Node, path string, record bool, override bool) (interface{}, error) {
+	mapping := make(map[interface{}]interface{}, len(node.Content)/2)
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Tag == "!!merge" {
+			merged = append(merged, valueNode)
+			continue
+		}
+		key, err := d.decode(keyNode, path, false, false)
+		if err != nil {
+			return nil, err
+		}
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, newYAMLNodeError(keyNode, "invalid map key: %#v", key)
+		}
+		if _, exist := mapping[key]; exist {
+			return nil, newYAMLNodeError(keyNode, "mapping key %#v already defined", key)
+		}
+		// Only string keys may be addressed by path.
+		name, addressable := key.(string)
+		keyPath := joinPath(path, name)
+		if _, exist := d.positions[keyPath]; addressable && record && (override || !exist) {
+			d.positions[keyPath] = Position{Line: keyNode.Line, Column: keyNode.Column}
+		}
+		if mapping[key], err = d.decode(valueNode, keyPath, record && addressable, override); err != nil {
+			return nil, err
+		}
+	}
+	for _, value := range merged {
+		values := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			values = value.Content
+		}
+		for _, value := range values {
+			if err := d.merge(mapping, value, path, record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// merge adds keys of merged mapping that are absent in map.
+func (d *yamlDecoder) merge(mapping map[interface{}]interface{}, node *yaml.Node, path string, record bool) error {
+	value, err := d.decode(node, path, record, false)
+	if err != nil {
+		return err
+	}
+	mergedMapping, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return newYAMLNodeError(node, "map merge requires map or sequence of maps as the value")
+	}
+	for key, element := range mergedMapping {
+		if _, exist := mapping[key]; !exist {
+			mapping[key] = element
+		}
+	}
+	return nil
+}
+
+// allowedYAMLAliasRatio returns allowed ratio of nodes decoded by aliases (as in 'yaml.v3').
+func allowedYAMLAliasRatio(decoded int) float64 {
+	switch {
+	case decoded <= 400000:
+		return 0.99
+	case decoded >= 4000000:
+		return 0.10
+	default:
+		return 0.99 - 0.89*(float64(decoded-400000)/3600000.0)
+	}
+}
+
+func newYAMLNodeError(node *yaml.Node, format string, args ...interface{}) error {
+	return &ParseError{Position: Position{Line: node.Line, Column: node.Column},
+		Err: fmt.Errorf(format, args...)}
+}
+
+// newYAMLParseError creates parse error. Parser reports only line of error, so column of the
+// first symbol of line is used.
+func newYAMLParseError(data []byte, err error) error {
+	parseError := newParseErrorFromMessage(err).(*ParseError)
+	lines := newLineIndex(data)
+	if line := parseError.Position.Line; line > 0 && line <= len(lines) {
+		offset := lines[line-1]
+		for offset < len(data) && (data[offset] == ' ' || data[offset] == '\t') {
+			offset++
+		}
+		parseError.Position.Column = offset - lines[line-1] + 1
+	}
+	return parseError
+}
+
+func (c *yamlConfig) typedValue(path string) (interface{}, error) {
+	return c.findElement(path)
+}
+
+func (c *yamlConfig) valueTree() interface{} {
+	return newValueTree(c.data)
+}
+
+func (c *yamlConfig) walkValues(visit valueVisitor) {
+	if c.data != nil {
+		walkTree(c.data, pathDelimiter, visit)
+	}
+}
+
+func (c *yamlConfig) loadRawValue(path string, value reflect.Value) (bool, error) {
+	element := c.data
+	if len(splitPath(path)) != 0 {
+		var err error
+		if element, err = c.findElement(path); err != nil {
+			return false, nil
+		}
+	}
+	if !useRawValue(value.Type(), yamlUnmarshalerType, element, isYAMLStructure) &&
+		!useRawValue(value.Type(), yamlObsoleteUnmarshalerType, element, isYAMLStructure) {
+		return false, nil
+	}
+	data, err := yaml.Marshal(element)
+	if err == nil {
+		err = yaml.Unmarshal(data, value.Addr().Interface())
+	}
+	return true, wrapPathError(c, path, "", err)
+}
+
+var (
+	yaml11Bools = map[string]bool{
+		"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+		"n": false, "N": false, "no": false, "No": false, "NO": false, "off": false, "Off": false,
+		"OFF": false}
+
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	// Unmarshaler interface of 'yaml.v2' is still supported by 'yaml.v3'.
+	yamlObsoleteUnmarshalerType = reflect.TypeOf((*interface {
+		UnmarshalYAML(unmarshal func(interface{}) error) error
+	})(nil)).Elem()
+)
+
+func isYAMLStructure(data interface{}) bool {
+	switch data.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
 func (c *yamlConfig) findElement(path string) (interface{}, error) {
+	element, _, err := c.resolveElement(path)
+	return element, err
+}
+
+// resolveElement returns element and path to it with keys written in config.
+func (c *yamlConfig) resolveElement(path string) (interface{}, string, error) {
 	element := c.data
 	pathParts := splitPath(path)
-	if len(pathParts) == 0 {
-		return nil, ErrorNotFound
+	if _, isSection := element.(map[interface{}]interface{}); isSection && len(pathParts) == 0 {
+		// Only scalar values and lists may be addressed by empty path.
+		return nil, "", ErrorNotFound
 	}
-	for _, pathPart := range pathParts {
+	for i, pathPart := range pathParts {
 		part, converted := element.(map[interface{}]interface{})
 		if !converted {
-			return nil, ErrorNotFound
+			return nil, "", ErrorNotFound
+		}
+		if c.normalizeKeys {
+			key, err := findNormalizedKey(getYAMLKeys(part), pathPart)
+			if err != nil {
+				return nil, "", err
+			}
+			pathParts[i] = key
 		}
 		var exist bool
-		if element, exist = part[pathPart]; !exist {
-			return nil, ErrorNotFound
+		if element, exist = part[pathParts[i]]; !exist {
+			return nil, "", ErrorNotFound
 		}
 	}
-	return element, nil
+	return element, joinPath(pathParts...), nil
+}
+
+// resolvePath returns path with keys written in config if keys are normalized.
+func (c *yamlConfig) resolvePath(path string) string {
+	if c.normalizeKeys {
+		if _, resolved, err := c.resolveElement(path); err == nil {
+			return resolved
+		}
+	}
+	return path
+}
+
+func (c *yamlConfig) withNormalizedKeys() Config {
+	config := *c
+	config.normalizeKeys = true
+	return &config
+}
+
+// getYAMLKeys returns string keys of mapping.
+func getYAMLKeys(mapping map[interface{}]interface{}) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		if name, ok := key.(string); ok {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // Yaml value parsers.
@@ -173,6 +483,10 @@ func parseYAMLInt(data interface{}) (value int64, err error) {
 	return parseJSONInt(data)
 }
 
+func parseYAMLUint(data interface{}) (value uint64, err error) {
+	return parseJSONUint(data)
+}
+
 // Grabbing helpers.
 func createYAMLValueGrabber(creator ValueSliceCreator, grabber ValueGrabber) ValueGrabber {
 	return createJSONValueGrabber(creator, grabber)
@@ -17,6 +17,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -168,7 +169,7 @@ func TestYamlGetValueEmptyPath(t *testing.T) {
 
 	for _, functors := range elementFunctors {
 		_, err = functors.Getter(config, "")
-		require.EqualError(t, err, ErrorNotFound.Error())
+		require.ErrorIs(t, err, ErrorNotFound)
 	}
 }
 
@@ -230,7 +231,7 @@ func TestYamlGrabAbsentValue(t *testing.T) {
 		return nil
 	})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -246,7 +247,7 @@ func TestYamlGrabAbsentValues(t *testing.T) {
 			return nil
 		})
 
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 	require.Equal(t, false, executed, "Value grabber must not be executed")
 }
 
@@ -259,7 +260,7 @@ func TestYamlGrabValuePassError(t *testing.T) {
 		return expectedError
 	})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 func TestYamlGrabValuesPassError(t *testing.T) {
@@ -273,7 +274,7 @@ func TestYamlGrabValuesPassError(t *testing.T) {
 			return expectedError
 		})
 
-	require.EqualError(t, err, expectedError.Error())
+	require.ErrorIs(t, err, expectedError)
 }
 
 func TestYamlGrabValuesOfSingleElement(t *testing.T) {
@@ -286,7 +287,7 @@ func TestYamlGrabValuesOfSingleElement(t *testing.T) {
 			return nil
 		})
 
-	require.EqualError(t, err, ErrorIncorrectValueType.Error())
+	require.ErrorIs(t, err, ErrorIncorrectValueType)
 }
 
 func TestYamlIncorrectInnerData(t *testing.T) {
@@ -294,7 +295,7 @@ func TestYamlIncorrectInnerData(t *testing.T) {
 
 	for element, functors := range elementFunctors {
 		_, err := functors.Getter(config, element)
-		require.EqualError(t, err, ErrorNotFound.Error())
+		require.ErrorIs(t, err, ErrorNotFound)
 	}
 }
 
@@ -363,6 +364,21 @@ func TestParseYamlInt(t *testing.T) {
 	require.EqualError(t, err, ErrorIncorrectValueType.Error())
 }
 
+func TestYamlGetUint(t *testing.T) {
+	config, err := newYAMLConfig([]byte("intElement: 18446744073709551615\nintElements: [1, 2, -3]"))
+	require.NoError(t, err, "Cannot parse yaml-config")
+
+	value, err := config.GetUint("/intElement")
+	require.NoError(t, err, "Cannot get value")
+	require.Equal(t, uint64(math.MaxUint64), value)
+
+	_, err = config.GetInt("/intElement")
+	require.ErrorIs(t, err, ErrorValueOverflow)
+
+	_, err = config.GetUints("/intElements", defaultArrayDelimiter)
+	require.ErrorIs(t, err, ErrorValueOverflow)
+}
+
 // Test GetConfigPart
 func TestYamlGetConfigPartRootFromRoot(t *testing.T) {
 	rootConfig, err := newYAMLConfig([]byte(twoLevelYAMLConfig))
@@ -384,7 +400,7 @@ func TestYamlGetConfigPartSectionFromRoot(t *testing.T) {
 	configPart, err := rootConfig.GetConfigPart("/root/child/grandchild/first")
 	require.NoError(t, err, "Cannot get config part")
 
-	require.Equal(t, configPart, expectedConfig, "Not equal configs")
+	require.Empty(t, Diff(expectedConfig, configPart), "Not equal configs")
 }
 
 func TestYamlGetConfigPartSectionFromSection(t *testing.T) {
@@ -408,10 +424,10 @@ func TestYamlGetConfigPartWithLongPath(t *testing.T) {
 	require.NoError(t, err, "Cannot get config section")
 
 	_, err = rootConfig.GetConfigPart("/root/child/grandchild/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 
 	_, err = configSection.GetConfigPart("/first/stringElement/element")
-	require.EqualError(t, err, ErrorNotFound.Error())
+	require.ErrorIs(t, err, ErrorNotFound)
 }
 
 func TestYamlGetAbsentConfigPart(t *testing.T) {
@@ -424,3 +440,79 @@ func TestYamlGetAbsentConfigPart(t *testing.T) {
 	_, err = config.GetConfigPart("/root/child/grandchild/third")
 	require.Error(t, err, ErrorNotFound.Error())
 }
+
+// Test loading of yaml.Unmarshaler values.
+type yamlUnmarshalerEndpoint struct {
+	Host string
+	Port int
+}
+
+func (e *yamlUnmarshalerEndpoint) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var address string
+	if err := unmarshal(&address); err == nil {
+		e.Host = address
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	e.Host, _ = fields["host"].(string)
+	e.Port, _ = fields["port"].(int)
+	return nil
+}
+
+func TestYamlLoadUnmarshalerValue(t *testing.T) {
+	config, err := newYAMLConfig([]byte(
+		"primary: {host: db1, port: 5432}\nreplicas:\n  - db2\n  - {host: db3, port: 5433}\n"))
+	require.NoError(t, err, "Cannot parse yaml-config")
+
+	var value struct {
+		Primary  yamlUnmarshalerEndpoint   `config:"primary"`
+		Replicas []yamlUnmarshalerEndpoint `config:"replicas"`
+	}
+	err = LoadValue(config, "/", &value)
+	require.NoError(t, err, "Cannot load value")
+	require.Equal(t, yamlUnmarshalerEndpoint{Host: "db1", Port: 5432}, value.Primary)
+	require.Equal(t, []yamlUnmarshalerEndpoint{{Host: "db2"}, {Host: "db3", Port: 5433}},
+		value.Replicas)
+}
+
+func TestYamlPositionsOfMergedKeys(t *testing.T) {
+	config, err := newYAMLConfig([]byte("base: &base\n  host: db0\n  port: 1\n" +
+		"server:\n  host: db1\n  <<: [*base, {port: 2, user: root}]\n  1: one\n  '1': string\n"))
+	require.NoError(t, err, "Cannot parse yaml-config")
+
+	for path, expected := range map[string]struct {
+		value    string
+		position Position
+	}{
+		"/server/host": {"db1", Position{Line: 5, Column: 3}},
+		"/server/port": {"1", Position{Line: 3, Column: 3}},
+		"/server/user": {"root", Position{Line: 6, Column: 25}},
+		"/server/1":    {"string", Position{Line: 8, Column: 3}},
+	} {
+		value, err := config.GetString(path)
+		require.NoError(t, err, path)
+		require.Equal(t, expected.value, value, path)
+		position, err := config.Position(path)
+		require.NoError(t, err, path)
+		require.Equal(t, expected.position, position, "Position must point to node of value %s", path)
+	}
+}
+
+func TestYamlDecodingErrors(t *testing.T) {
+	_, err := newYAMLConfig([]byte("server:\n  host: db1\n  host: db2\n"))
+	require.EqualError(t, err, "3:3: mapping key \"host\" already defined")
+
+	_, err = newYAMLConfig([]byte("server:\n  <<: 1\n"))
+	require.EqualError(t, err, "2:7: map merge requires map or sequence of maps as the value")
+
+	laughs := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	for i := 'b'; i <= 'j'; i++ {
+		laughs += fmt.Sprintf("%c: &%c [*%c, *%c, *%c, *%c, *%c, *%c, *%c, *%c, *%c, *%c]\n",
+			i, i, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1)
+	}
+	_, err = newYAMLConfig([]byte(laughs))
+	require.ErrorContains(t, err, "document contains excessive aliasing")
+}